@@ -0,0 +1,114 @@
+package customtypes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Duration is a go duration string. Two durations are semantically equal when
+// they parse to the same length of time, so "1h" and "60m" never show a diff
+type Duration struct {
+	basetypes.StringValue
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = (*Duration)(nil)
+var _ xattr.ValidateableAttribute = (*Duration)(nil)
+
+func NewDurationNull() Duration {
+	return Duration{
+		StringValue: basetypes.NewStringNull(),
+	}
+}
+
+func NewDurationUnknown() Duration {
+	return Duration{
+		StringValue: basetypes.NewStringUnknown(),
+	}
+}
+
+func NewDurationValue(value string) Duration {
+	return Duration{
+		StringValue: basetypes.NewStringValue(value),
+	}
+}
+
+// NewDurationSeconds creates a duration from a number of seconds
+func NewDurationSeconds(sec int64) Duration {
+	return NewDurationValue((time.Duration(sec) * time.Second).String())
+}
+
+func (v Duration) Type(context.Context) attr.Type {
+	return DurationType{}
+}
+
+func (v Duration) Equal(o attr.Value) bool {
+	other, ok := o.(Duration)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v Duration) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(Duration)
+	if !ok {
+		diags.AddError(
+			"semantic equality check error",
+			fmt.Sprintf("expected value type %T, got %T", v, newValuable),
+		)
+		return false, diags
+	}
+
+	priorDuration, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	newDuration, err := time.ParseDuration(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	return priorDuration == newDuration, diags
+}
+
+func (v Duration) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if _, err := time.ParseDuration(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"invalid duration",
+			fmt.Sprintf("duration must be a go duration such as 1h or 40m: %s", err.Error()),
+		)
+	}
+}
+
+// ValueDuration parses the duration. It must only be called on known values
+func (v Duration) ValueDuration() (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	duration, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		diags.AddError("invalid duration", err.Error())
+	}
+
+	return duration, diags
+}
+
+// ValueSeconds returns the duration in whole seconds
+func (v Duration) ValueSeconds() (int64, diag.Diagnostics) {
+	duration, diags := v.ValueDuration()
+	return int64(duration / time.Second), diags
+}
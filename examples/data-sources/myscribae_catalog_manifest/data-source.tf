@@ -0,0 +1,28 @@
+data "myscribae_catalog_manifest" "example" {
+  path = "${path.module}/catalog.yaml"
+}
+
+resource "myscribae_script_group" "example" {
+  for_each = data.myscribae_catalog_manifest.example.groups
+
+  provider_id = myscribae_provider.example.id
+  alt_id      = each.value.alt_id
+  name        = each.value.name
+  description = each.value.description
+  public      = each.value.public
+}
+
+resource "myscribae_script" "example" {
+  for_each = data.myscribae_catalog_manifest.example.scripts
+
+  provider_id        = myscribae_provider.example.id
+  script_group_id    = myscribae_script_group.example[each.value.script_group_alt_id].id
+  alt_id             = each.value.alt_id
+  name               = each.value.name
+  description        = each.value.description
+  recurrence         = each.value.recurrence
+  price_in_cents     = each.value.price_in_cents
+  sla_sec            = each.value.sla_sec
+  token_lifetime_sec = each.value.token_lifetime_sec
+  public             = each.value.public
+}
@@ -0,0 +1,14 @@
+data "myscribae_current_identity" "current" {}
+
+output "myscribae_account" {
+  value = data.myscribae_current_identity.current.email
+}
+
+resource "terraform_data" "deploy" {
+  lifecycle {
+    precondition {
+      condition     = contains(data.myscribae_current_identity.current.scopes, "catalog:write")
+      error_message = "The MyScribae credentials cannot write the catalog."
+    }
+  }
+}
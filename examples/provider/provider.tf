@@ -1,3 +1,10 @@
 provider "myscribae" {
   api_token = "<USER API TOKEN>"
+
+  default_tags {
+    tags = {
+      managed_by  = "terraform"
+      environment = "production"
+    }
+  }
 }
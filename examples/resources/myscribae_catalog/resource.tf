@@ -0,0 +1,21 @@
+resource "myscribae_catalog" "example" {
+  provider_id = myscribae_provider.example.id
+
+  group {
+    alt_id      = "example_script_group"
+    name        = "Example Group"
+    description = "Example group is a group of scripts"
+    public      = true
+
+    script {
+      alt_id             = "example_script"
+      name               = "Example Script"
+      description        = "Example script is a script"
+      recurrence         = "monthly"
+      price_in_cents     = 1000
+      sla_sec            = 3600
+      token_lifetime_sec = 1800
+      public             = true
+    }
+  }
+}
@@ -0,0 +1,2 @@
+# Providers are imported by their uuid
+terraform import myscribae_provider.example 3fa85f64-5717-4562-b3fc-2c963f66afa6
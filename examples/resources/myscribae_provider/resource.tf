@@ -5,4 +5,10 @@ resource "myscribae_provider" "example" {
   logo_url    = "https://netflix.com/images/logo.png"
   color       = "#E50914"
   public      = true
+
+  tags = {
+    team        = "billing"
+    cost_center = "cc-1042"
+    environment = "production"
+  }
 }
\ No newline at end of file
@@ -0,0 +1,2 @@
+# Scripts are imported by <provider_id>/<script_group_id>/<uuid or alt_id>
+terraform import myscribae_script.example 3fa85f64-5717-4562-b3fc-2c963f66afa6/9b2c1d4e-8f3a-4c5b-a6d7-e8f9a0b1c2d3/summarize
@@ -0,0 +1,2 @@
+# Script groups are imported by <provider_id>/<uuid or alt_id>
+terraform import myscribae_script_group.example 3fa85f64-5717-4562-b3fc-2c963f66afa6/core-tools
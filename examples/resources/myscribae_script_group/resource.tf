@@ -4,4 +4,10 @@ resource "myscribae_script_group" "example" {
   name        = "Example Group"
   description = "Example group is a group of scripts"
   public      = false
+
+  tags = {
+    team        = "billing"
+    cost_center = "cc-1042"
+    environment = "production"
+  }
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	provider "github.com/myscribae/myscribae-terraform-provider/internal/provider"
+)
+
+// stringsFlag collects a flag given more than once
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runExport writes the given providers to .tf files with import blocks, so an
+// account built by hand can be managed by terraform. The api cannot list the
+// providers of an account, so each is given by its uuid:
+//
+//	terraform-provider-myscribae export -dir ./myscribae -provider-id <uuid>
+//
+// The credentials are read as the provider block reads them when it sets no
+// attributes: from MYSCRIBAE_API_TOKEN, MYSCRIBAE_API_KEY and
+// MYSCRIBAE_SECRET_KEY, then the credentials profile
+func runExport(args []string) error {
+	var (
+		opts        = provider.ExportOptions{Version: version}
+		providerIds stringsFlag
+	)
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.StringVar(&opts.Dir, "dir", ".", "the directory to write the .tf files to")
+	flags.StringVar(&opts.Profile, "profile", "", "the profile of ~/.myscribae/credentials to read (default MYSCRIBAE_PROFILE, then default)")
+	flags.Var(&providerIds, "provider-id", "the uuid of a provider to export, may be given more than once. The api cannot list the providers of an account, so without it only the default_provider_id of the profile is exported")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	opts.ProviderIds = providerIds
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	files, err := provider.Export(ctx, opts)
+	for _, file := range files {
+		fmt.Println("wrote", file)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("run terraform plan to review the imports, then terraform apply to take them over")
+	return nil
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hasura/go-graphql-client"
+	"github.com/myscribae/myscribae-sdk-go/gql"
+	"github.com/myscribae/myscribae-sdk-go/provider"
+)
+
+// catalogQuery reads every script group of a provider with its scripts in one
+// query, so a refresh costs one request per provider rather than one per
+// script group and script
+type catalogQuery struct {
+	ProviderSelf struct {
+		ScriptGroups []struct {
+			Uuid        uuid.UUID              `graphql:"uuid"`
+			AltID       string                 `graphql:"alt_id"`
+			Name        string                 `graphql:"name"`
+			Description string                 `graphql:"description"`
+			Public      bool                   `graphql:"public"`
+			Scripts     []gql.GQLScriptProfile `graphql:"scripts"`
+		} `graphql:"script_groups"`
+	} `graphql:"provider_self(id:$provider_id)"`
+}
+
+// fetchedCatalog holds the script groups and scripts of a provider, keyed by
+// both alt id and uuid. Scripts are keyed by their script group and their own
+// id. groups lists them once each, in the order the api returned them
+type fetchedCatalog struct {
+	scriptGroups map[string]*gql.ScriptGroupProfile
+	scripts      map[string]*gql.ScriptProfile
+	groups       []fetchedScriptGroup
+}
+
+type fetchedScriptGroup struct {
+	profile *gql.ScriptGroupProfile
+	scripts []*gql.ScriptProfile
+}
+
+func fetchedScriptKey(scriptGroupId string, scriptId string) string {
+	return scriptGroupId + "/" + scriptId
+}
+
+func queryCatalog(ctx context.Context, prov *provider.Provider) (*fetchedCatalog, error) {
+	var query catalogQuery
+	if err := prov.Client.Query(ctx, &query, map[string]interface{}{
+		"provider_id": prov.ID(),
+	}); err != nil {
+		return nil, err
+	}
+
+	catalog := &fetchedCatalog{
+		scriptGroups: map[string]*gql.ScriptGroupProfile{},
+		scripts:      map[string]*gql.ScriptProfile{},
+	}
+
+	for _, g := range query.ProviderSelf.ScriptGroups {
+		scriptGroup := &gql.ScriptGroupProfile{
+			Uuid:        g.Uuid,
+			AltID:       g.AltID,
+			Name:        g.Name,
+			Description: g.Description,
+			Public:      g.Public,
+		}
+		scriptGroupIds := []string{g.Uuid.String(), g.AltID}
+		for _, id := range scriptGroupIds {
+			catalog.scriptGroups[id] = scriptGroup
+		}
+		fetched := fetchedScriptGroup{profile: scriptGroup}
+
+		for _, s := range g.Scripts {
+			script := &gql.ScriptProfile{
+				Uuid:             s.Uuid,
+				AltID:            s.AltID,
+				Name:             s.Name,
+				Description:      s.Description,
+				Recurrence:       s.Recurrence,
+				PriceInCents:     s.PriceInCents,
+				SlaSec:           uint(s.SlaSec),
+				TokenLifetimeSec: uint(s.TokenLifetimeSec),
+				Public:           s.Public,
+			}
+			for _, scriptGroupId := range scriptGroupIds {
+				catalog.scripts[fetchedScriptKey(scriptGroupId, s.Uuid.String())] = script
+				catalog.scripts[fetchedScriptKey(scriptGroupId, s.AltID)] = script
+			}
+			fetched.scripts = append(fetched.scripts, script)
+		}
+		catalog.groups = append(catalog.groups, fetched)
+	}
+
+	return catalog, nil
+}
+
+// fetchCatalog returns the catalog of prov, querying it the first time any of
+// its script groups or scripts is read. When the api does not know the query,
+// it is not queried again and ok is false, so reads fall back to single
+// queries. Any other failure is returned
+func (p *myScribaeProvider) fetchCatalog(ctx context.Context, prov *provider.Provider) (catalog *fetchedCatalog, ok bool, err error) {
+	p.reads.mu.Lock()
+	unsupported := p.reads.catalogUnsupported
+	p.reads.mu.Unlock()
+	if unsupported {
+		return nil, false, nil
+	}
+
+	catalog, err = cachedRead(ctx, &p.reads, fmt.Sprintf("catalog/%s", prov.ID()), func(ctx context.Context) (*fetchedCatalog, error) {
+		return queryCatalog(ctx, prov)
+	})
+	if err != nil {
+		if !unknownFieldError(err) {
+			return nil, false, err
+		}
+
+		tflog.Warn(ctx, "the api does not support fetching the catalog in one query, reading script groups and scripts one at a time", map[string]interface{}{
+			"provider_id": prov.ID(),
+			"error":       err,
+		})
+
+		p.reads.mu.Lock()
+		p.reads.catalogUnsupported = true
+		p.reads.mu.Unlock()
+		return nil, false, nil
+	}
+
+	return catalog, true, nil
+}
+
+// unknownFieldError reports whether err is the api failing to validate a query
+// for a field or query its schema does not have. Failed requests, such as
+// cancellations, timeouts, rate limits or rejected credentials, are not
+func unknownFieldError(err error) bool {
+	var errs graphql.Errors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return false
+	}
+
+	for _, e := range errs {
+		code, _ := e.Extensions["code"].(string)
+		switch code {
+		case graphql.ErrRequestError, graphql.ErrJsonDecode, graphql.ErrGraphQLEncode, graphql.ErrGraphQLDecode:
+			return false
+		}
+
+		message := strings.ToLower(e.Message)
+		if !strings.Contains(message, "cannot query field") &&
+			!strings.Contains(message, "not found in type") &&
+			!strings.Contains(message, "unknown field") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myscribae/myscribae-terraform-provider/validators"
+	"gopkg.in/yaml.v3"
+)
+
+// A catalog manifest is a YAML or JSON file describing script groups and
+// their scripts:
+//
+//	groups:
+//	  - alt_id: example_group
+//	    name: Example Group
+//	    description: Example group is a group of scripts
+//	    public: true
+//	    scripts:
+//	      - alt_id: example_script
+//	        name: Example Script
+//	        description: Example script is a script
+//	        recurrence: monthly
+//	        price_in_cents: 1000
+//	        sla: 1h
+//	        token_lifetime: 30m
+//
+// Every value is checked with the same rules as the resource schemas and
+// problems are reported with the line of the file they were found on.
+
+type catalogManifestGroup struct {
+	AltID       types.String `tfsdk:"alt_id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Public      types.Bool   `tfsdk:"public"`
+}
+
+type catalogManifestScript struct {
+	AltID            types.String `tfsdk:"alt_id"`
+	ScriptGroupAltID types.String `tfsdk:"script_group_alt_id"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	Recurrence       types.String `tfsdk:"recurrence"`
+	PriceInCents     types.Int64  `tfsdk:"price_in_cents"`
+	SlaSec           types.Int64  `tfsdk:"sla_sec"`
+	TokenLifetimeSec types.Int64  `tfsdk:"token_lifetime_sec"`
+	Public           types.Bool   `tfsdk:"public"`
+}
+
+type catalogManifest struct {
+	Groups  map[string]catalogManifestGroup
+	Scripts map[string]catalogManifestScript
+}
+
+type catalogManifestParser struct {
+	file        string
+	recurrences []string
+	diags       diag.Diagnostics
+}
+
+// parseCatalogManifest parses the manifest in content, read from file.
+// Recurrences are checked against recurrences, or only for their shape when
+// the provider is not configured
+func parseCatalogManifest(ctx context.Context, file string, content []byte, recurrences []string) (*catalogManifest, diag.Diagnostics) {
+	p := &catalogManifestParser{
+		file:        file,
+		recurrences: recurrences,
+	}
+	manifest := &catalogManifest{
+		Groups:  map[string]catalogManifestGroup{},
+		Scripts: map[string]catalogManifestScript{},
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		p.diags.AddAttributeError(
+			path.Root("path"),
+			"failed to parse catalog manifest",
+			fmt.Sprintf("%s: %s", file, err),
+		)
+		return nil, p.diags
+	}
+	if len(document.Content) == 0 {
+		p.diags.AddAttributeError(path.Root("path"), "empty catalog manifest", fmt.Sprintf("%s: the manifest has no content", file))
+		return nil, p.diags
+	}
+
+	root := document.Content[0]
+	fields := p.fields(root, "manifest", "groups")
+	groupsNode, ok := fields["groups"]
+	if !ok {
+		return manifest, p.diags
+	}
+	if groupsNode.Kind != yaml.SequenceNode {
+		p.errorAt(groupsNode, "groups", "invalid catalog manifest", "must be a list of script groups")
+		return manifest, p.diags
+	}
+
+	groupLines := map[string]int{}
+	scriptLines := map[string]int{}
+
+	for i, groupNode := range groupsNode.Content {
+		groupField := fmt.Sprintf("groups[%d]", i)
+		groupFields := p.fields(groupNode, groupField, "alt_id", "name", "description", "public", "scripts")
+
+		group := catalogManifestGroup{
+			AltID: p.string(ctx, groupNode, groupFields, groupField, "alt_id", true,
+				validators.NewAltIdValidator(true),
+			),
+			Name: p.string(ctx, groupNode, groupFields, groupField, "name", true,
+				stringvalidator.LengthBetween(1, 100),
+			),
+			Description: p.string(ctx, groupNode, groupFields, groupField, "description", true,
+				stringvalidator.LengthBetween(3, 500),
+			),
+			Public: p.bool(groupFields, groupField, "public"),
+		}
+
+		groupAltId := group.AltID.ValueString()
+		if !group.AltID.IsNull() {
+			if line, ok := groupLines[groupAltId]; ok {
+				p.errorAt(groupFields["alt_id"], groupField+".alt_id", "duplicate script group alt id",
+					fmt.Sprintf("the script group alt id %q is already used on line %d", groupAltId, line))
+			} else {
+				groupLines[groupAltId] = groupFields["alt_id"].Line
+				manifest.Groups[groupAltId] = group
+			}
+		}
+
+		scriptsNode, ok := groupFields["scripts"]
+		if !ok {
+			continue
+		}
+		if scriptsNode.Kind != yaml.SequenceNode {
+			p.errorAt(scriptsNode, groupField+".scripts", "invalid catalog manifest", "must be a list of scripts")
+			continue
+		}
+
+		for j, scriptNode := range scriptsNode.Content {
+			scriptField := fmt.Sprintf("%s.scripts[%d]", groupField, j)
+			script, scriptFields := p.script(ctx, scriptNode, scriptField)
+			script.ScriptGroupAltID = group.AltID
+
+			if script.AltID.IsNull() {
+				continue
+			}
+
+			altId := script.AltID.ValueString()
+			if line, ok := scriptLines[altId]; ok {
+				p.errorAt(scriptFields["alt_id"], scriptField+".alt_id", "duplicate script alt id",
+					fmt.Sprintf("the script alt id %q is already used on line %d", altId, line))
+				continue
+			}
+			scriptLines[altId] = scriptFields["alt_id"].Line
+			manifest.Scripts[altId] = script
+		}
+	}
+
+	return manifest, p.diags
+}
+
+func (p *catalogManifestParser) script(ctx context.Context, node *yaml.Node, field string) (catalogManifestScript, map[string]*yaml.Node) {
+	fields := p.fields(node, field,
+		"alt_id", "name", "description", "recurrence", "price_in_cents",
+		"sla", "sla_sec", "token_lifetime", "token_lifetime_sec", "public",
+	)
+
+	script := catalogManifestScript{
+		AltID: p.string(ctx, node, fields, field, "alt_id", true,
+			validators.NewAltIdValidator(true),
+		),
+		Name: p.string(ctx, node, fields, field, "name", true,
+			stringvalidator.LengthBetween(1, 100),
+		),
+		Description: p.string(ctx, node, fields, field, "description", true,
+			stringvalidator.LengthBetween(3, 500),
+		),
+		Recurrence: p.string(ctx, node, fields, field, "recurrence", true,
+			validators.NewRecurrenceValidator(p.recurrences...),
+		),
+		PriceInCents: p.int64(ctx, node, fields, field, "price_in_cents", true,
+			int64validator.Between(1, math.MaxUint32),
+		),
+		SlaSec:           p.seconds(ctx, node, fields, field, "sla", "sla_sec", 2400),
+		TokenLifetimeSec: p.seconds(ctx, node, fields, field, "token_lifetime", "token_lifetime_sec", 600),
+		Public:           p.bool(fields, field, "public"),
+	}
+
+	if !script.SlaSec.IsNull() && !script.TokenLifetimeSec.IsNull() && script.TokenLifetimeSec.ValueInt64() > script.SlaSec.ValueInt64() {
+		tokenLifetimeNode, ok := fields["token_lifetime"]
+		if !ok {
+			tokenLifetimeNode = fields["token_lifetime_sec"]
+		}
+		p.errorAt(tokenLifetimeNode, field+".token_lifetime", "token lifetime exceeds sla",
+			fmt.Sprintf("the token lifetime (%ds) must not be longer than the sla (%ds)", script.TokenLifetimeSec.ValueInt64(), script.SlaSec.ValueInt64()))
+	}
+
+	return script, fields
+}
+
+// fields returns the values of the mapping node keyed by name, reporting keys
+// that are not allowed or repeated
+func (p *catalogManifestParser) fields(node *yaml.Node, field string, allowed ...string) map[string]*yaml.Node {
+	fields := map[string]*yaml.Node{}
+	if node.Kind != yaml.MappingNode {
+		p.errorAt(node, field, "invalid catalog manifest", "must be a mapping")
+		return fields
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !slices.Contains(allowed, key.Value) {
+			p.errorAt(key, field, "invalid catalog manifest",
+				fmt.Sprintf("unknown key %q, expected one of %s", key.Value, strings.Join(allowed, ", ")))
+			continue
+		}
+		if prior, ok := fields[key.Value]; ok {
+			p.errorAt(key, field+"."+key.Value, "invalid catalog manifest",
+				fmt.Sprintf("%s is already set on line %d", key.Value, prior.Line))
+			continue
+		}
+		fields[key.Value] = value
+	}
+
+	return fields
+}
+
+func (p *catalogManifestParser) string(ctx context.Context, parent *yaml.Node, fields map[string]*yaml.Node, field string, key string, required bool, stringValidators ...validator.String) types.String {
+	node, ok := fields[key]
+	if !ok {
+		if required {
+			p.errorAt(parent, field, "missing catalog manifest value", key+" is required")
+		}
+		return types.StringNull()
+	}
+
+	var value string
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		p.errorAt(node, field+"."+key, "invalid catalog manifest value", "must be a string")
+		return types.StringNull()
+	}
+
+	result := types.StringValue(value)
+	for _, v := range stringValidators {
+		resp := &validator.StringResponse{}
+		v.ValidateString(ctx, validator.StringRequest{Path: path.Root(key), ConfigValue: result}, resp)
+		p.report(node, field+"."+key, resp.Diagnostics)
+	}
+
+	return result
+}
+
+func (p *catalogManifestParser) int64(ctx context.Context, parent *yaml.Node, fields map[string]*yaml.Node, field string, key string, required bool, int64Validators ...validator.Int64) types.Int64 {
+	node, ok := fields[key]
+	if !ok {
+		if required {
+			p.errorAt(parent, field, "missing catalog manifest value", key+" is required")
+		}
+		return types.Int64Null()
+	}
+
+	var value int64
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		p.errorAt(node, field+"."+key, "invalid catalog manifest value", "must be a whole number")
+		return types.Int64Null()
+	}
+
+	result := types.Int64Value(value)
+	for _, v := range int64Validators {
+		resp := &validator.Int64Response{}
+		v.ValidateInt64(ctx, validator.Int64Request{Path: path.Root(key), ConfigValue: result}, resp)
+		p.report(node, field+"."+key, resp.Diagnostics)
+	}
+
+	return result
+}
+
+// bool returns the optional bool at key, which defaults to false
+func (p *catalogManifestParser) bool(fields map[string]*yaml.Node, field string, key string) types.Bool {
+	node, ok := fields[key]
+	if !ok {
+		return types.BoolValue(false)
+	}
+
+	var value bool
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		p.errorAt(node, field+"."+key, "invalid catalog manifest value", "must be true or false")
+		return types.BoolValue(false)
+	}
+
+	return types.BoolValue(value)
+}
+
+// seconds returns the seconds of a duration given either as a go duration at
+// durationKey or in seconds at secKey, both of which must be at least min
+func (p *catalogManifestParser) seconds(ctx context.Context, parent *yaml.Node, fields map[string]*yaml.Node, field string, durationKey string, secKey string, min int64) types.Int64 {
+	durationNode, hasDuration := fields[durationKey]
+	_, hasSec := fields[secKey]
+
+	switch {
+	case hasDuration && hasSec:
+		p.errorAt(durationNode, field+"."+durationKey, "invalid catalog manifest value",
+			fmt.Sprintf("only one of %s and %s can be set", durationKey, secKey))
+		return types.Int64Null()
+	case hasSec:
+		return p.int64(ctx, parent, fields, field, secKey, true, int64validator.Between(min, math.MaxUint32))
+	case !hasDuration:
+		p.errorAt(parent, field, "missing catalog manifest value", fmt.Sprintf("one of %s and %s is required", durationKey, secKey))
+		return types.Int64Null()
+	}
+
+	value := p.string(ctx, parent, fields, field, durationKey, true,
+		validators.NewDurationValidator(time.Duration(min)*time.Second, math.MaxUint32*time.Second),
+	)
+	if value.IsNull() {
+		return types.Int64Null()
+	}
+
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		p.errorAt(durationNode, field+"."+durationKey, "invalid duration", err.Error())
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(int64(duration / time.Second))
+}
+
+func (p *catalogManifestParser) errorAt(node *yaml.Node, field string, summary string, detail string) {
+	p.diags.AddAttributeError(
+		path.Root("path"),
+		summary,
+		fmt.Sprintf("%s:%d: %s: %s", p.file, node.Line, field, detail),
+	)
+}
+
+// report moves the diagnostics of a validator onto the line of node
+func (p *catalogManifestParser) report(node *yaml.Node, field string, diags diag.Diagnostics) {
+	for _, d := range diags {
+		p.errorAt(node, field, d.Summary(), d.Detail())
+	}
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var _ datasource.DataSource = (*catalogManifestDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*catalogManifestDataSource)(nil)
+
+type catalogManifestDataSource struct {
+	terraformProvider *myScribaeProvider
+}
+
+type catalogManifestDataSourceData struct {
+	Id      types.String                     `tfsdk:"id"`
+	Path    types.String                     `tfsdk:"path"`
+	Groups  map[string]catalogManifestGroup  `tfsdk:"groups"`
+	Scripts map[string]catalogManifestScript `tfsdk:"scripts"`
+}
+
+func newCatalogManifestDataSource() datasource.DataSource {
+	return &catalogManifestDataSource{}
+}
+
+func (e *catalogManifestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "myscribae_catalog_manifest"
+}
+
+func (e *catalogManifestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	prov, ok := req.ProviderData.(*myScribaeProvider)
+	if !ok {
+		resp.Diagnostics.AddError("invalid provider data", "expected *myScribaeProvider")
+		return
+	}
+
+	e.terraformProvider = prov
+}
+
+func (e *catalogManifestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads script groups and scripts from a local YAML or JSON catalog manifest, checking them with the same rules as the resources. The groups and scripts are keyed by alt id so they can be used with for_each",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The id of the catalog manifest, which is its path",
+				Computed:    true,
+			},
+			"path": schema.StringAttribute{
+				Description: "The path of the YAML or JSON catalog manifest",
+				Required:    true,
+			},
+			"groups": schema.MapNestedAttribute{
+				Description: "The script groups of the catalog manifest, keyed by alt id",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"alt_id": schema.StringAttribute{
+							Description: "The alt id of the script group",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the script group",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "The description of the script group",
+							Computed:    true,
+						},
+						"public": schema.BoolAttribute{
+							Description: "The public status of the script group (default false)",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"scripts": schema.MapNestedAttribute{
+				Description: "The scripts of the catalog manifest, keyed by alt id",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"alt_id": schema.StringAttribute{
+							Description: "The alt id of the script",
+							Computed:    true,
+						},
+						"script_group_alt_id": schema.StringAttribute{
+							Description: "The alt id of the script group of the script",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the script",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "The description of the script",
+							Computed:    true,
+						},
+						"recurrence": schema.StringAttribute{
+							Description: "The recurrence of the script",
+							Computed:    true,
+						},
+						"price_in_cents": schema.Int64Attribute{
+							Description: "The price in cents of the script",
+							Computed:    true,
+						},
+						"sla_sec": schema.Int64Attribute{
+							Description: "The SLA in seconds of the script, from either sla or sla_sec in the manifest",
+							Computed:    true,
+						},
+						"token_lifetime_sec": schema.Int64Attribute{
+							Description: "The token lifetime in seconds of the script, from either token_lifetime or token_lifetime_sec in the manifest",
+							Computed:    true,
+						},
+						"public": schema.BoolAttribute{
+							Description: "The public status of the script (default false)",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (e *catalogManifestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	data := &catalogManifestDataSourceData{}
+	if diags := req.Config.Get(ctx, data); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	file := data.Path.ValueString()
+	content, err := os.ReadFile(file)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "failed to read catalog manifest", err.Error())
+		return
+	}
+
+	var recurrences []string
+	if e.terraformProvider != nil && e.terraformProvider.Client != nil {
+		recurrences = e.terraformProvider.Recurrences(ctx)
+	}
+
+	manifest, diags := parseCatalogManifest(ctx, file, content, recurrences)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &catalogManifestDataSourceData{
+		Id:      basetypes.NewStringValue(file),
+		Path:    data.Path,
+		Groups:  manifest.Groups,
+		Scripts: manifest.Scripts,
+	})...)
+}
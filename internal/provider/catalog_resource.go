@@ -0,0 +1,906 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/myscribae/myscribae-sdk-go/provider"
+	"github.com/myscribae/myscribae-sdk-go/utilities"
+	"github.com/myscribae/myscribae-terraform-provider/validators"
+)
+
+var _ resource.Resource = (*catalogResource)(nil)
+var _ resource.ResourceWithConfigure = (*catalogResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*catalogResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*catalogResource)(nil)
+
+type catalogResource struct {
+	// validationProvider is the provider the resource was created by, which
+	// validation reads before terraformProvider is configured
+	validationProvider *myScribaeProvider
+	terraformProvider  *myScribaeProvider
+	myscribaeProvider  *provider.Provider
+}
+
+type catalogResourceData struct {
+	Id          types.String       `tfsdk:"id"`
+	ProviderId  types.String       `tfsdk:"provider_id"`
+	Groups      []catalogGroupData `tfsdk:"group"`
+	GroupUuids  types.Map          `tfsdk:"group_uuids"`
+	ScriptUuids types.Map          `tfsdk:"script_uuids"`
+}
+
+type catalogGroupData struct {
+	AltID       types.String        `tfsdk:"alt_id"`
+	Name        types.String        `tfsdk:"name"`
+	Description types.String        `tfsdk:"description"`
+	Public      types.Bool          `tfsdk:"public"`
+	Scripts     []catalogScriptData `tfsdk:"script"`
+}
+
+type catalogScriptData struct {
+	AltID            types.String `tfsdk:"alt_id"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	Recurrence       types.String `tfsdk:"recurrence"`
+	PriceInCents     types.Int64  `tfsdk:"price_in_cents"`
+	SlaSec           types.Int64  `tfsdk:"sla_sec"`
+	TokenLifetimeSec types.Int64  `tfsdk:"token_lifetime_sec"`
+	Public           types.Bool   `tfsdk:"public"`
+}
+
+func newCatalogResource(p *myScribaeProvider) resource.Resource {
+	return &catalogResource{validationProvider: p}
+}
+
+func (e *catalogResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "myscribae_catalog"
+}
+
+func (e *catalogResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	prov, ok := req.ProviderData.(*myScribaeProvider)
+	if !ok {
+		resp.Diagnostics.AddError("invalid provider data", "expected *myScribaeProvider")
+		return
+	}
+	e.terraformProvider = prov
+}
+
+func (e *catalogResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the script groups and scripts of a provider as one resource. Only the groups and scripts that differ from the live catalog are changed on apply. Groups and scripts that already exist are adopted, and those removed from the catalog are unpublished. Groups and scripts the catalog never held, such as those of myscribae_script resources, are left alone",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The id of the catalog, which is the provider id",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"provider_id": schema.StringAttribute{
+				Description: "The provider id of the catalog (defaults to the default_provider_id of the provider). Changing it replaces the catalog",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.NewUuidValidator(false),
+				},
+			},
+			"group_uuids": schema.MapAttribute{
+				Description: "The uuids of the script groups, keyed by alt id",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"script_uuids": schema.MapAttribute{
+				Description: "The uuids of the scripts, keyed by alt id",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"group": schema.ListNestedBlock{
+				Description: "A script group of the catalog",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"alt_id": schema.StringAttribute{
+							Description: "The alt id of the script group, unique within the catalog",
+							Required:    true,
+							Validators: []validator.String{
+								validators.NewAltIdValidator(true),
+							},
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the script group",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 100),
+							},
+						},
+						"description": schema.StringAttribute{
+							Description: "The description of the script group",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(3, 500),
+							},
+						},
+						"public": schema.BoolAttribute{
+							Description: "Is the script group public (default false)",
+							Optional:    true,
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"script": schema.ListNestedBlock{
+							Description: "A script of the script group",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"alt_id": schema.StringAttribute{
+										Description: "The alt id of the script, unique within the catalog. Moving a script block to another group moves the script in place, keeping its uuid and subscribers",
+										Required:    true,
+										Validators: []validator.String{
+											validators.NewAltIdValidator(true),
+										},
+									},
+									"name": schema.StringAttribute{
+										Description: "The name of the script",
+										Required:    true,
+										Validators: []validator.String{
+											stringvalidator.LengthBetween(1, 100),
+										},
+									},
+									"description": schema.StringAttribute{
+										Description: "The description of the script",
+										Required:    true,
+										Validators: []validator.String{
+											stringvalidator.LengthBetween(3, 500),
+										},
+									},
+									"recurrence": schema.StringAttribute{
+										Description: "The recurrence of the script (one of the recurrences supported by the api, e.g. monthly). It cannot be changed once the script exists",
+										Required:    true,
+										Validators: []validator.String{
+											validators.NewRecurrenceValidator(),
+										},
+									},
+									"price_in_cents": schema.Int64Attribute{
+										Description: "The price in cents of the script (between 1 and 4294967295)",
+										Required:    true,
+										Validators: []validator.Int64{
+											int64validator.Between(1, math.MaxUint32),
+										},
+									},
+									"sla_sec": schema.Int64Attribute{
+										Description: "The SLA in seconds of the script (between 2400 and 4294967295, must not be shorter than the token lifetime)",
+										Required:    true,
+										Validators: []validator.Int64{
+											int64validator.Between(2400, math.MaxUint32),
+										},
+									},
+									"token_lifetime_sec": schema.Int64Attribute{
+										Description: "The token lifetime in seconds of the script (between 600 and 4294967295, must not exceed the sla)",
+										Required:    true,
+										Validators: []validator.Int64{
+											int64validator.Between(600, math.MaxUint32),
+										},
+									},
+									"public": schema.BoolAttribute{
+										Description: "Is the script public (default false)",
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (e *catalogResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		catalogValidator{},
+		providerIdValidator{prov: e.validationProvider},
+	}
+}
+
+func (e *catalogResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planProviderId(ctx, req, resp, e.terraformProvider, true)
+
+	var groups types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("group"), &groups)...)
+	if resp.Diagnostics.HasError() || groups.IsUnknown() {
+		return
+	}
+
+	planData := catalogResourceData{}
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	priorRecurrences := map[string]types.String{}
+	if !req.State.Raw.IsNull() {
+		stateData := catalogResourceData{}
+		resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		priorGroups := map[string]string{}
+		for _, g := range stateData.Groups {
+			for _, s := range g.Scripts {
+				priorRecurrences[s.AltID.ValueString()] = s.Recurrence
+				priorGroups[s.AltID.ValueString()] = g.AltID.ValueString()
+			}
+		}
+
+		// the uuids only change when groups or scripts are added or removed,
+		// moving a script to another group keeps its uuid
+		groupAltIds, scriptAltIds := map[string]bool{}, map[string]bool{}
+		for _, g := range planData.Groups {
+			groupAltIds[g.AltID.ValueString()] = true
+			for _, s := range g.Scripts {
+				scriptAltIds[s.AltID.ValueString()] = true
+
+				priorGroup, ok := priorGroups[s.AltID.ValueString()]
+				if !ok || priorGroup == g.AltID.ValueString() {
+					continue
+				}
+				resp.Diagnostics.AddWarning(
+					"script will be moved",
+					fmt.Sprintf(
+						"Script %q will be moved from group %q to %q in place. It keeps its uuid and subscribers.",
+						s.AltID.ValueString(),
+						priorGroup,
+						g.AltID.ValueString(),
+					),
+				)
+			}
+		}
+		if sameKeys(groupAltIds, stateData.GroupUuids) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("group_uuids"), stateData.GroupUuids)...)
+		}
+		if sameKeys(scriptAltIds, stateData.ScriptUuids) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("script_uuids"), stateData.ScriptUuids)...)
+		}
+	}
+
+	if e.terraformProvider == nil {
+		return
+	}
+
+	// the recurrences are only known once the provider is configured, so
+	// membership is checked here rather than in the schema validator
+	recurrenceValidator := validators.NewRecurrenceValidator(e.terraformProvider.Recurrences(ctx)...)
+	for i, g := range planData.Groups {
+		for j, s := range g.Scripts {
+			if s.AltID.IsUnknown() || s.Recurrence.IsUnknown() {
+				continue
+			}
+
+			recurrencePath := path.Root("group").AtListIndex(i).AtName("script").AtListIndex(j).AtName("recurrence")
+			prior, exists := priorRecurrences[s.AltID.ValueString()]
+			if exists {
+				if !prior.Equal(s.Recurrence) {
+					resp.Diagnostics.AddAttributeError(
+						recurrencePath,
+						"recurrence cannot be changed",
+						fmt.Sprintf(
+							"The api cannot change the recurrence of the existing script %q from %s to %s. Give the script a new alt_id to create a new one instead.",
+							s.AltID.ValueString(),
+							prior.ValueString(),
+							s.Recurrence.ValueString(),
+						),
+					)
+				}
+				continue
+			}
+
+			validatorResp := &validator.StringResponse{}
+			recurrenceValidator.ValidateString(ctx, validator.StringRequest{
+				Path:        recurrencePath,
+				ConfigValue: s.Recurrence,
+			}, validatorResp)
+			for _, d := range validatorResp.Diagnostics {
+				resp.Diagnostics.AddAttributeError(recurrencePath, d.Summary(), d.Detail())
+			}
+		}
+	}
+}
+
+func (e *catalogResource) MakeClient(ctx context.Context, providerId string) error {
+	providerUuid, err := uuid.Parse(providerId)
+	if err != nil {
+		return err
+	}
+
+	e.myscribaeProvider = e.terraformProvider.providerClient(providerUuid)
+
+	return nil
+}
+
+func (e *catalogResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	planData := catalogResourceData{}
+	if diags := req.Plan.Get(ctx, &planData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := e.MakeClient(ctx, planData.ProviderId.ValueString()); err != nil {
+		resp.Diagnostics.AddError("failed to make client for create", err.Error())
+		return
+	}
+
+	// groups and scripts that already exist are adopted rather than created
+	current, diags := e.liveCatalog(ctx, &planData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	save := func() {
+		state, diags := current.data(ctx, planData.ProviderId, planData.Groups)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+	e.apply(ctx, current, &planData, save, &resp.Diagnostics)
+	save()
+}
+
+func (e *catalogResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	stateData := catalogResourceData{}
+	if diags := req.State.Get(ctx, &stateData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := e.MakeClient(ctx, stateData.ProviderId.ValueString()); err != nil {
+		resp.Diagnostics.AddError("failed to make client for read", err.Error())
+		return
+	}
+
+	// only the groups and scripts in state are read, published ones the
+	// catalog never held may belong to other resources
+	current, diags := e.liveCatalog(ctx, &stateData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, g := range stateData.Groups {
+		groupAltId := g.AltID.ValueString()
+		if group, ok := current.groups[groupAltId]; ok {
+			group.Public = catalogBool(g.Public, group.Public.ValueBool())
+			current.groups[groupAltId] = group
+		}
+
+		for _, s := range g.Scripts {
+			altId := s.AltID.ValueString()
+			if script, ok := current.scripts[altId]; ok {
+				script.data.Public = catalogBool(s.Public, script.data.Public.ValueBool())
+				current.scripts[altId] = script
+			}
+		}
+	}
+
+	state, diags := current.data(ctx, stateData.ProviderId, stateData.Groups)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (e *catalogResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	stateData := catalogResourceData{}
+	if diags := req.State.Get(ctx, &stateData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	planData := catalogResourceData{}
+	if diags := req.Plan.Get(ctx, &planData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := e.MakeClient(ctx, stateData.ProviderId.ValueString()); err != nil {
+		resp.Diagnostics.AddError("failed to make client for update", err.Error())
+		return
+	}
+
+	current, diags := e.liveCatalog(ctx, &stateData, &planData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	save := func() {
+		state, diags := current.data(ctx, planData.ProviderId, planData.Groups)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+	e.apply(ctx, current, &planData, save, &resp.Diagnostics)
+	save()
+}
+
+func (e *catalogResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	stateData := catalogResourceData{}
+	if diags := req.State.Get(ctx, &stateData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := e.MakeClient(ctx, stateData.ProviderId.ValueString()); err != nil {
+		resp.Diagnostics.AddError("failed to make client for delete", err.Error())
+		return
+	}
+
+	current, diags := e.liveCatalog(ctx, &stateData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// applying an empty catalog unpublishes every group and script. The
+	// state only outlives the delete when it fails
+	save := func() {
+		state, diags := current.data(ctx, stateData.ProviderId, stateData.Groups)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+	e.apply(ctx, current, &catalogResourceData{ProviderId: stateData.ProviderId}, save, &resp.Diagnostics)
+}
+
+// apply issues the mutations that turn current into planned. current is kept
+// up to date and saved as each mutation succeeds, so a failed apply still
+// records what was changed. Groups are created before their scripts and
+// scripts removed before their groups
+func (e *catalogResource) apply(ctx context.Context, current *catalog, planned *catalogResourceData, save func(), diags *diag.Diagnostics) {
+	defer e.terraformProvider.mutated(ctx)
+
+	plannedGroups := map[string]bool{}
+	plannedScripts := map[string]bool{}
+
+	for _, g := range planned.Groups {
+		altId := g.AltID.ValueString()
+		plannedGroups[altId] = true
+		g.Scripts = nil
+
+		existing, ok := current.groups[altId]
+		if !ok {
+			scriptGroup, err := e.myscribaeProvider.ScriptGroup(altId)
+			if err != nil {
+				diags.AddError(fmt.Sprintf("failed to create script group client for %s", altId), err.Error())
+				return
+			}
+
+			resultUuid, err := scriptGroup.Create(ctx, provider.CreateScriptGroupInput{
+				Name:        g.Name.ValueString(),
+				Description: g.Description.ValueString(),
+				Public:      g.Public.ValueBool(),
+			})
+			if err != nil {
+				diags.AddError(fmt.Sprintf("failed to create script group %s", altId), err.Error())
+				return
+			}
+
+			current.groups[altId] = g
+			current.groupUuids[altId] = resultUuid.String()
+			save()
+			continue
+		}
+
+		if existing.Name.Equal(g.Name) && existing.Description.Equal(g.Description) && existing.Public.ValueBool() == g.Public.ValueBool() {
+			current.groups[altId] = g
+			continue
+		}
+
+		scriptGroup, err := e.myscribaeProvider.ScriptGroup(current.groupUuids[altId])
+		if err != nil {
+			diags.AddError(fmt.Sprintf("failed to create script group client for %s", altId), err.Error())
+			return
+		}
+
+		public := g.Public.ValueBool()
+		if err := editScriptGroup(ctx, scriptGroup, provider.UpdateScriptGroupInput{
+			Name:        g.Name.ValueStringPointer(),
+			Description: g.Description.ValueStringPointer(),
+			Public:      &public,
+		}); err != nil {
+			diags.AddError(fmt.Sprintf("failed to update script group %s", altId), err.Error())
+			return
+		}
+
+		current.groups[altId] = g
+		save()
+	}
+
+	for _, g := range planned.Groups {
+		groupAltId := g.AltID.ValueString()
+		for _, s := range g.Scripts {
+			altId := s.AltID.ValueString()
+			plannedScripts[altId] = true
+
+			existing, ok := current.scripts[altId]
+			if !ok {
+				script, err := e.script(current, groupAltId, altId)
+				if err != nil {
+					diags.AddError(fmt.Sprintf("failed to create script client for %s", altId), err.Error())
+					return
+				}
+
+				resultUuid, err := script.Create(ctx, provider.CreateScriptInput{
+					AltID:            altId,
+					Name:             s.Name.ValueString(),
+					Description:      s.Description.ValueString(),
+					Recurrence:       utilities.Recurrence(s.Recurrence.ValueString()),
+					PriceInCents:     utilities.NewCentValue(uint(s.PriceInCents.ValueInt64())),
+					SlaSec:           utilities.NewUInt(uint(s.SlaSec.ValueInt64())),
+					TokenLifetimeSec: utilities.NewUInt(uint(s.TokenLifetimeSec.ValueInt64())),
+					Public:           s.Public.ValueBool(),
+				})
+				if err != nil {
+					diags.AddError(fmt.Sprintf("failed to create script %s", altId), err.Error())
+					return
+				}
+
+				current.scripts[altId] = catalogScript{group: groupAltId, data: s}
+				current.scriptUuids[altId] = resultUuid.String()
+				save()
+				continue
+			}
+
+			if existing.group != groupAltId {
+				script, err := e.script(current, existing.group, altId)
+				if err != nil {
+					diags.AddError(fmt.Sprintf("failed to create script client for %s", altId), err.Error())
+					return
+				}
+
+				if err := moveScript(ctx, script, current.groupUuids[groupAltId]); err != nil {
+					diags.AddError(fmt.Sprintf("failed to move script %s to %s", altId, groupAltId), err.Error())
+					return
+				}
+
+				existing.group = groupAltId
+				current.scripts[altId] = existing
+				save()
+			}
+
+			if !existing.data.Recurrence.Equal(s.Recurrence) {
+				diags.AddError(
+					fmt.Sprintf("failed to update script %s", altId),
+					fmt.Sprintf("The api cannot change the recurrence of the existing script from %s to %s.", existing.data.Recurrence.ValueString(), s.Recurrence.ValueString()),
+				)
+				return
+			}
+
+			if existing.data.Name.Equal(s.Name) &&
+				existing.data.Description.Equal(s.Description) &&
+				existing.data.PriceInCents.Equal(s.PriceInCents) &&
+				existing.data.SlaSec.Equal(s.SlaSec) &&
+				existing.data.TokenLifetimeSec.Equal(s.TokenLifetimeSec) &&
+				existing.data.Public.ValueBool() == s.Public.ValueBool() {
+				current.scripts[altId] = catalogScript{group: groupAltId, data: s}
+				continue
+			}
+
+			script, err := e.script(current, groupAltId, altId)
+			if err != nil {
+				diags.AddError(fmt.Sprintf("failed to create script client for %s", altId), err.Error())
+				return
+			}
+
+			var (
+				priceInCents     = utilities.NewCentValue(uint(s.PriceInCents.ValueInt64()))
+				slaSec           = utilities.NewUInt(uint(s.SlaSec.ValueInt64()))
+				tokenLifetimeSec = utilities.NewUInt(uint(s.TokenLifetimeSec.ValueInt64()))
+				public           = s.Public.ValueBool()
+			)
+
+			if _, err := script.Update(ctx, provider.UpdateScriptInput{
+				Name:             s.Name.ValueStringPointer(),
+				Description:      s.Description.ValueStringPointer(),
+				PriceInCents:     &priceInCents,
+				SlaSec:           &slaSec,
+				TokenLifetimeSec: &tokenLifetimeSec,
+				Public:           &public,
+			}); err != nil {
+				diags.AddError(fmt.Sprintf("failed to update script %s", altId), err.Error())
+				return
+			}
+
+			current.scripts[altId] = catalogScript{group: groupAltId, data: s}
+			save()
+		}
+	}
+
+	for _, altId := range sortedKeys(current.scripts) {
+		if plannedScripts[altId] {
+			continue
+		}
+
+		script, err := e.script(current, current.scripts[altId].group, altId)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("failed to create script client for %s", altId), err.Error())
+			return
+		}
+
+		if err := script.Delete(ctx); err != nil {
+			diags.AddError(fmt.Sprintf("failed to delete script %s", altId), err.Error())
+			return
+		}
+
+		delete(current.scripts, altId)
+		delete(current.scriptUuids, altId)
+		save()
+	}
+
+	for _, altId := range sortedKeys(current.groups) {
+		if plannedGroups[altId] {
+			continue
+		}
+
+		scriptGroup, err := e.myscribaeProvider.ScriptGroup(current.groupUuids[altId])
+		if err != nil {
+			diags.AddError(fmt.Sprintf("failed to create script group client for %s", altId), err.Error())
+			return
+		}
+
+		public := false
+		if err := editScriptGroup(ctx, scriptGroup, provider.UpdateScriptGroupInput{
+			Public: &public,
+		}); err != nil {
+			diags.AddError(fmt.Sprintf("failed to delete script group %s", altId), err.Error())
+			return
+		}
+
+		delete(current.groups, altId)
+		delete(current.groupUuids, altId)
+		save()
+	}
+}
+
+// script returns a client for the script altId in the group groupAltId,
+// resolving both by uuid once they exist
+func (e *catalogResource) script(current *catalog, groupAltId string, altId string) (*provider.Script, error) {
+	scriptGroupId, err := utilities.NewAltUuid(current.groupUuids[groupAltId])
+	if err != nil {
+		return nil, err
+	}
+
+	if scriptUuid, ok := current.scriptUuids[altId]; ok {
+		return e.myscribaeProvider.Script(scriptGroupId, scriptUuid)
+	}
+
+	return e.myscribaeProvider.Script(scriptGroupId, altId)
+}
+
+type catalogScript struct {
+	group string
+	data  catalogScriptData
+}
+
+// catalog is the catalog keyed by alt id, which makes it easy to diff and
+// to update as mutations succeed
+type catalog struct {
+	groups      map[string]catalogGroupData
+	scripts     map[string]catalogScript
+	groupUuids  map[string]string
+	scriptUuids map[string]string
+}
+
+// liveCatalog reads the catalog of the provider as the api has it, keeping
+// only the groups and scripts of managed whether published or not. Anything
+// else belongs to other resources or to nobody, and is left alone
+func (e *catalogResource) liveCatalog(ctx context.Context, managed ...*catalogResourceData) (*catalog, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	fetched, ok, err := e.terraformProvider.fetchCatalog(ctx, e.myscribaeProvider)
+	if err != nil {
+		diags.AddError("failed to read the catalog", err.Error())
+		return nil, diags
+	}
+	if !ok {
+		diags.AddError(
+			"failed to read the catalog",
+			"The catalog resource diffs against every script group and script of the provider, which the api did not return.",
+		)
+		return nil, diags
+	}
+
+	managedGroups := map[string]bool{}
+	managedScripts := map[string]string{}
+	for _, data := range managed {
+		for _, g := range data.Groups {
+			managedGroups[g.AltID.ValueString()] = true
+			for _, s := range g.Scripts {
+				managedScripts[s.AltID.ValueString()] = g.AltID.ValueString()
+			}
+		}
+	}
+
+	c := &catalog{
+		groups:      map[string]catalogGroupData{},
+		scripts:     map[string]catalogScript{},
+		groupUuids:  map[string]string{},
+		scriptUuids: map[string]string{},
+	}
+
+	for _, g := range fetched.groups {
+		groupAltId := g.profile.AltID
+		for _, s := range g.scripts {
+			managedGroup, isManaged := managedScripts[s.AltID]
+			if !isManaged {
+				continue
+			}
+
+			// an alt id can be in several groups, such as after a script
+			// was recreated in another one. The one in its managed group
+			// wins, then the published one
+			if existing, ok := c.scripts[s.AltID]; ok {
+				if existing.group == managedGroup {
+					continue
+				}
+				if groupAltId != managedGroup && (existing.data.Public.ValueBool() || !s.Public) {
+					continue
+				}
+			}
+
+			c.scripts[s.AltID] = catalogScript{
+				group: groupAltId,
+				data: catalogScriptData{
+					AltID:            basetypes.NewStringValue(s.AltID),
+					Name:             basetypes.NewStringValue(s.Name),
+					Description:      basetypes.NewStringValue(s.Description),
+					Recurrence:       basetypes.NewStringValue(s.Recurrence),
+					PriceInCents:     basetypes.NewInt64Value(int64(s.PriceInCents)),
+					SlaSec:           basetypes.NewInt64Value(int64(s.SlaSec)),
+					TokenLifetimeSec: basetypes.NewInt64Value(int64(s.TokenLifetimeSec)),
+					Public:           basetypes.NewBoolValue(s.Public),
+				},
+			}
+			c.scriptUuids[s.AltID] = s.Uuid.String()
+		}
+	}
+
+	// a kept script keeps its group too
+	scriptGroups := map[string]bool{}
+	for _, s := range c.scripts {
+		scriptGroups[s.group] = true
+	}
+
+	for _, g := range fetched.groups {
+		altId := g.profile.AltID
+		if !managedGroups[altId] && !scriptGroups[altId] {
+			continue
+		}
+
+		c.groups[altId] = catalogGroupData{
+			AltID:       basetypes.NewStringValue(altId),
+			Name:        basetypes.NewStringValue(g.profile.Name),
+			Description: basetypes.NewStringValue(g.profile.Description),
+			Public:      basetypes.NewBoolValue(g.profile.Public),
+		}
+		c.groupUuids[altId] = g.profile.Uuid.String()
+	}
+
+	return c, diags
+}
+
+// data converts the catalog back into resource data, keeping the order of
+// groups and scripts in order. Anything not in order, such as groups a failed
+// apply could not remove, follows sorted by alt id
+func (c *catalog) data(ctx context.Context, providerId types.String, order []catalogGroupData) (catalogResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	groupOrder := []string{}
+	scriptOrder := map[string][]string{}
+	seenGroups := map[string]bool{}
+	seenScripts := map[string]bool{}
+
+	for _, g := range order {
+		groupAltId := g.AltID.ValueString()
+		if _, ok := c.groups[groupAltId]; ok && !seenGroups[groupAltId] {
+			groupOrder = append(groupOrder, groupAltId)
+			seenGroups[groupAltId] = true
+		}
+		for _, s := range g.Scripts {
+			altId := s.AltID.ValueString()
+			if script, ok := c.scripts[altId]; ok && script.group == groupAltId && !seenScripts[altId] {
+				scriptOrder[groupAltId] = append(scriptOrder[groupAltId], altId)
+				seenScripts[altId] = true
+			}
+		}
+	}
+	for _, altId := range sortedKeys(c.groups) {
+		if !seenGroups[altId] {
+			groupOrder = append(groupOrder, altId)
+		}
+	}
+	for _, altId := range sortedKeys(c.scripts) {
+		if !seenScripts[altId] {
+			group := c.scripts[altId].group
+			scriptOrder[group] = append(scriptOrder[group], altId)
+		}
+	}
+
+	result := catalogResourceData{
+		Id:         providerId,
+		ProviderId: providerId,
+		Groups:     []catalogGroupData{},
+	}
+	for _, groupAltId := range groupOrder {
+		group := c.groups[groupAltId]
+		group.Scripts = []catalogScriptData{}
+		for _, altId := range scriptOrder[groupAltId] {
+			group.Scripts = append(group.Scripts, c.scripts[altId].data)
+		}
+		result.Groups = append(result.Groups, group)
+	}
+
+	var d diag.Diagnostics
+	result.GroupUuids, d = types.MapValueFrom(ctx, types.StringType, c.groupUuids)
+	diags.Append(d...)
+	result.ScriptUuids, d = types.MapValueFrom(ctx, types.StringType, c.scriptUuids)
+	diags.Append(d...)
+
+	return result, diags
+}
+
+// catalogBool keeps an unset optional bool unset while the live value is
+// still the default of false
+func catalogBool(prior types.Bool, live bool) types.Bool {
+	if prior.IsNull() && !live {
+		return prior
+	}
+
+	return basetypes.NewBoolValue(live)
+}
+
+func sameKeys(keys map[string]bool, m types.Map) bool {
+	if m.IsNull() || m.IsUnknown() || len(keys) != len(m.Elements()) {
+		return false
+	}
+
+	for k := range m.Elements() {
+		if !keys[k] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
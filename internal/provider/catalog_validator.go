@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// catalogValidator rejects catalogs the diff cannot be computed for, where two
+// groups or two scripts share an alt id, and applies the script timing rule to
+// every script
+type catalogValidator struct{}
+
+var _ resource.ConfigValidator = (*catalogValidator)(nil)
+
+func (v catalogValidator) Description(ctx context.Context) string {
+	return "group and script alt ids must be unique within the catalog and token lifetimes must not exceed the sla"
+}
+
+func (v catalogValidator) MarkdownDescription(ctx context.Context) string {
+	return "group and script alt ids must be unique within the catalog and token lifetimes must not exceed the sla"
+}
+
+func (v catalogValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var groupList types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("group"), &groupList)...)
+	if resp.Diagnostics.HasError() || groupList.IsNull() || groupList.IsUnknown() {
+		return
+	}
+
+	var groups []catalogGroupData
+	resp.Diagnostics.Append(groupList.ElementsAs(ctx, &groups, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupPaths := map[string]path.Path{}
+	scriptPaths := map[string]path.Path{}
+
+	for i, g := range groups {
+		groupPath := path.Root("group").AtListIndex(i)
+		if !g.AltID.IsNull() && !g.AltID.IsUnknown() {
+			altId := g.AltID.ValueString()
+			if prior, ok := groupPaths[altId]; ok {
+				resp.Diagnostics.AddAttributeError(
+					groupPath.AtName("alt_id"),
+					"duplicate script group alt id",
+					fmt.Sprintf("the script group alt id %q is already used at %s", altId, prior),
+				)
+			} else {
+				groupPaths[altId] = groupPath.AtName("alt_id")
+			}
+		}
+
+		for j, s := range g.Scripts {
+			scriptPath := groupPath.AtName("script").AtListIndex(j)
+			if !s.AltID.IsNull() && !s.AltID.IsUnknown() {
+				altId := s.AltID.ValueString()
+				if prior, ok := scriptPaths[altId]; ok {
+					resp.Diagnostics.AddAttributeError(
+						scriptPath.AtName("alt_id"),
+						"duplicate script alt id",
+						fmt.Sprintf("the script alt id %q is already used at %s", altId, prior),
+					)
+				} else {
+					scriptPaths[altId] = scriptPath.AtName("alt_id")
+				}
+			}
+
+			if s.SlaSec.IsNull() || s.SlaSec.IsUnknown() || s.TokenLifetimeSec.IsNull() || s.TokenLifetimeSec.IsUnknown() {
+				continue
+			}
+
+			if s.TokenLifetimeSec.ValueInt64() > s.SlaSec.ValueInt64() {
+				resp.Diagnostics.AddAttributeError(
+					scriptPath.AtName("token_lifetime_sec"),
+					"token lifetime exceeds sla",
+					fmt.Sprintf("the token lifetime (%ds) must not be longer than the sla (%ds)", s.TokenLifetimeSec.ValueInt64(), s.SlaSec.ValueInt64()),
+				)
+			}
+		}
+	}
+}
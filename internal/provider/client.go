@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hasura/go-graphql-client"
+)
+
+const (
+	apiTokenHeader  = "X-MyScribae-ApiToken"
+	apiKeyHeader    = "X-MyScribae-ApiKey"
+	secretKeyHeader = "X-MyScribae-SecretKey"
+	userAgentHeader = "User-Agent"
+
+	// requestTimeout bounds each request once the limiter lets it through,
+	// as the 5 second timeout of gql.CreateGraphQLClient does
+	requestTimeout = 5 * time.Second
+
+	// appendUserAgentEnvVar is read by every HashiCorp provider, so automation
+	// can tag the traffic of all its providers at once
+	appendUserAgentEnvVar = "TF_APPEND_USER_AGENT"
+)
+
+// userAgent identifies the provider and terraform versions to the api, as
+// terraform-provider-myscribae/<version> terraform/<version> <suffix>
+func userAgent(providerVersion string, terraformVersion string, suffix string) string {
+	if providerVersion == "" {
+		providerVersion = "dev"
+	}
+
+	parts := []string{"terraform-provider-myscribae/" + providerVersion}
+	if terraformVersion != "" {
+		parts = append(parts, "terraform/"+terraformVersion)
+	}
+	for _, extra := range []string{os.Getenv(appendUserAgentEnvVar), suffix} {
+		if extra = strings.TrimSpace(extra); extra != "" {
+			parts = append(parts, extra)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// newApiClient builds the graphql client shared by every resource and data
+// source. It matches gql.CreateGraphQLClient apart from sending every request
+// through limiter, adding headers and authenticating it with creds over
+// transport. The timeout is applied by limitedTransport rather than the
+// client, so time spent queued in the limiter does not count against it
+func newApiClient(apiUrl string, creds apiCredentials, limiter *requestLimiter, transport http.RoundTripper, headers map[string]string) *graphql.Client {
+	httpClient := &http.Client{
+		Transport: &limitedTransport{
+			limiter: limiter,
+			timeout: requestTimeout,
+			next: &headerTransport{
+				headers: headers,
+				next: &authTransport{
+					creds: creds,
+					next:  transport,
+				},
+			},
+		},
+	}
+
+	return graphql.NewClient(apiUrl, httpClient)
+}
+
+// unauthorizedError is returned for requests the api rejects as
+// unauthenticated or forbidden, so callers can tell bad credentials from other
+// failures
+type unauthorizedError struct {
+	status string
+	body   string
+}
+
+func (e *unauthorizedError) Error() string {
+	return "api rejected the credentials: " + e.reason()
+}
+
+// reason is the response status, followed by the body when there is one
+func (e *unauthorizedError) reason() string {
+	if e.body == "" {
+		return e.status
+	}
+	return e.status + ": " + e.body
+}
+
+// authTransport authenticates every request. When the api rejects a token
+// that can be refreshed, the request is retried once with a fresh token
+type authTransport struct {
+	creds apiCredentials
+	next  http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var token string
+	if t.creds.tokens != nil {
+		var err error
+		if token, err = t.creds.tokens.Token(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return unauthorized(resp, err)
+	}
+
+	tokens, ok := t.creds.tokens.(refreshableTokenSource)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return unauthorized(resp, nil)
+	}
+
+	tokens.Expire(token)
+	fresh, err := tokens.Token(ctx)
+	if err != nil || fresh == token {
+		return unauthorized(resp, nil)
+	}
+
+	tflog.Debug(ctx, "api rejected the token, retrying with a fresh one")
+	resp.Body.Close()
+	return unauthorized(t.send(req, fresh))
+}
+
+// unauthorized turns a 401 or 403 response into an unauthorizedError, keeping the
+// start of the response body for the diagnostic
+func unauthorized(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, &unauthorizedError{status: resp.Status, body: strings.TrimSpace(string(body))}
+}
+
+func (t *authTransport) send(req *http.Request, token string) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	if token != "" {
+		r.Header.Set(apiTokenHeader, token)
+	}
+	// the sdk sends the keys of the provider it acts for on some requests,
+	// and a complete pair takes precedence over the keys of the provider
+	// block. Half a pair is replaced, so keys of two providers are never mixed
+	if t.creds.apiKey != "" && (r.Header.Get(apiKeyHeader) == "" || r.Header.Get(secretKeyHeader) == "") {
+		r.Header.Set(apiKeyHeader, t.creds.apiKey)
+		r.Header.Set(secretKeyHeader, t.creds.secretKey)
+	}
+
+	return t.next.RoundTrip(r)
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/myscribae/myscribae-sdk-go/environment"
+)
+
+const (
+	apiUrlEnvVar   = environment.ApiUrlEnvVar
+	apiTokenEnvVar = "MYSCRIBAE_API_TOKEN"
+	profileEnvVar  = "MYSCRIBAE_PROFILE"
+
+	defaultApiUrl  = "https://api.myscribae.com"
+	defaultProfile = "default"
+)
+
+// credentialsProfile is a named section of the credentials file:
+//
+//	[default]
+//	api_url             = https://api.myscribae.com
+//	api_token           = "..."
+//	api_key             = "..."
+//	secret_key          = "..."
+//	default_provider_id = 3fa85f64-5717-4562-b3fc-2c963f66afa6
+//
+// The file is read as INI, and values may be quoted so it is valid TOML too
+type credentialsProfile struct {
+	Name              string
+	File              string
+	ApiUrl            string
+	ApiToken          string
+	ApiKey            string
+	SecretKey         string
+	DefaultProviderId string
+}
+
+// source describes where the profile was read from for diagnostics
+func (c *credentialsProfile) source() string {
+	return fmt.Sprintf("profile %q of %s", c.Name, c.File)
+}
+
+// credentials returns the api token or key pair of the profile, if it has
+// either
+func (c *credentialsProfile) credentials() (apiCredentials, bool) {
+	switch {
+	case c == nil:
+		return apiCredentials{}, false
+	case c.ApiToken != "":
+		return apiCredentials{tokens: staticToken(c.ApiToken)}, true
+	case c.ApiKey != "" && c.SecretKey != "":
+		return apiCredentials{apiKey: c.ApiKey, secretKey: c.SecretKey}, true
+	}
+
+	return apiCredentials{}, false
+}
+
+// apiCredentials authenticate requests with either an api token or a pair of
+// api and secret keys
+type apiCredentials struct {
+	tokens    tokenSource
+	apiKey    string
+	secretKey string
+}
+
+// credentialsFile returns the path of the credentials file, ~/.myscribae/credentials
+func credentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".myscribae", "credentials"), nil
+}
+
+// loadCredentialsProfile reads the profile name from the credentials file.
+// A profile that was not asked for explicitly may be missing, in which case
+// it returns nil
+func loadCredentialsProfile(name string, explicit bool) (*credentialsProfile, error) {
+	file, err := credentialsFile()
+	if err != nil {
+		if explicit {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := parseCredentials(file, content)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		if !explicit {
+			return nil, nil
+		}
+
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %q not found in %s, which has the profiles: %s", name, file, strings.Join(names, ", "))
+	}
+
+	return profile, nil
+}
+
+func parseCredentials(file string, content []byte) (map[string]*credentialsProfile, error) {
+	profiles := map[string]*credentialsProfile{}
+	var current *credentialsProfile
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("%s:%d: invalid profile header %q", file, line, text)
+			}
+			name := unquote(strings.TrimSpace(text[1 : len(text)-1]))
+			if _, ok := profiles[name]; ok {
+				return nil, fmt.Errorf("%s:%d: profile %q is already defined", file, line, name)
+			}
+			current = &credentialsProfile{Name: name, File: file}
+			profiles[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", file, line)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%s:%d: %s is set outside of a profile", file, line, strings.TrimSpace(key))
+		}
+
+		key, value = strings.TrimSpace(key), unquote(strings.TrimSpace(value))
+		switch key {
+		case "api_url":
+			current.ApiUrl = value
+		case "api_token":
+			current.ApiToken = value
+		case "api_key":
+			current.ApiKey = value
+		case "secret_key":
+			current.SecretKey = value
+		case "default_provider_id":
+			if !isUuid(value) {
+				return nil, fmt.Errorf("%s:%d: default_provider_id must be a uuid, got %q", file, line, value)
+			}
+			current.DefaultProviderId = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q, expected one of api_url, api_token, api_key, secret_key, default_provider_id", file, line, key)
+		}
+	}
+
+	return profiles, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
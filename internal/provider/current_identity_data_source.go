@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*currentIdentityDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*currentIdentityDataSource)(nil)
+
+type currentIdentityDataSource struct {
+	terraformProvider *myScribaeProvider
+}
+
+type currentIdentityDataSourceData struct {
+	Id             types.String `tfsdk:"id"`
+	Email          types.String `tfsdk:"email"`
+	Name           types.String `tfsdk:"name"`
+	Scopes         types.List   `tfsdk:"scopes"`
+	TokenExpiresAt types.String `tfsdk:"token_expires_at"`
+	ApiUrl         types.String `tfsdk:"api_url"`
+}
+
+func newCurrentIdentityDataSource() datasource.DataSource {
+	return &currentIdentityDataSource{}
+}
+
+func (e *currentIdentityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "myscribae_current_identity"
+}
+
+func (e *currentIdentityDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	prov, ok := req.ProviderData.(*myScribaeProvider)
+	if !ok {
+		resp.Diagnostics.AddError("invalid provider data", "expected *myScribaeProvider")
+		return
+	}
+
+	e.terraformProvider = prov
+}
+
+func (e *currentIdentityDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The account the credentials of the provider belong to, read with the me query of the api. The sdk does not model that query, so the data source fails against an api without it",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The account id of the credentials",
+				Computed:    true,
+			},
+			"email": schema.StringAttribute{
+				Description: "The email of the account (null when the account has none)",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the account (null when the account has none)",
+				Computed:    true,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "The scopes granted to the credentials",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"token_expires_at": schema.StringAttribute{
+				Description: "When the api token expires, as an RFC 3339 time (null when it does not expire or the credentials are an api key pair)",
+				Computed:    true,
+			},
+			"api_url": schema.StringAttribute{
+				Description: "The url of the MyScribae API the credentials were checked against",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (e *currentIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	me, err := queryIdentity(ctx, e.terraformProvider.Client, e.terraformProvider.creds.tokens)
+	var unauthorized *unauthorizedError
+	if errors.As(err, &unauthorized) {
+		resp.Diagnostics.AddError("invalid api credentials", fmt.Sprintf("The credentials from %s were rejected: %s", e.terraformProvider.CredentialsSource, unauthorized))
+		return
+	}
+	if unknownFieldError(err) {
+		resp.Diagnostics.AddError(
+			"error reading current identity",
+			fmt.Sprintf("The MyScribae API at %s does not offer the me query this data source reads: %s", e.terraformProvider.ApiUrl, err),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error reading current identity", err.Error())
+		return
+	}
+
+	scopes, diags := types.ListValueFrom(ctx, types.StringType, me.Scopes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenExpiresAt := types.StringNull()
+	if me.TokenExpiresAt != nil {
+		tokenExpiresAt = types.StringValue(me.TokenExpiresAt.UTC().Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &currentIdentityDataSourceData{
+		Id:             types.StringValue(me.Uuid.String()),
+		Email:          types.StringPointerValue(me.Email),
+		Name:           types.StringPointerValue(me.Name),
+		Scopes:         scopes,
+		TokenExpiresAt: tokenExpiresAt,
+		ApiUrl:         types.StringValue(e.terraformProvider.ApiUrl),
+	})...)
+}
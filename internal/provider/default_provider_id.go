@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ provider.ProviderWithValidateConfig = (*myScribaeProvider)(nil)
+
+const missingProviderIdDetail = "provider_id must be set here, or default_provider_id must be set on the myscribae provider block or in its credentials profile"
+
+// providerId returns configured, or the default_provider_id of the provider
+// when it is not set
+func (p *myScribaeProvider) providerId(configured types.String) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !configured.IsNull() {
+		return configured, diags
+	}
+
+	if p.DefaultProviderId == "" {
+		diags.AddAttributeError(path.Root("provider_id"), "missing provider id", missingProviderIdDetail)
+		return configured, diags
+	}
+
+	return types.StringValue(p.DefaultProviderId), diags
+}
+
+// planProviderId plans the default_provider_id of the provider for a
+// resource that does not set provider_id. With requiresReplace, a change of
+// the planned provider id replaces the resource, as does a provider id only
+// known at apply, the way RequiresReplaceIfConfigured treats it
+func planProviderId(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, prov *myScribaeProvider, requiresReplace bool) {
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("provider_id"), &configured)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if configured.IsUnknown() {
+		if !requiresReplace || req.State.Raw.IsNull() {
+			return
+		}
+
+		var state types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("provider_id"), &state)...)
+		if !resp.Diagnostics.HasError() && !state.IsNull() && !state.IsUnknown() {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("provider_id"))
+		}
+		return
+	}
+
+	planned := configured
+	if configured.IsNull() {
+		if prov == nil {
+			// the default is not known until the provider is configured
+			return
+		}
+
+		var diags diag.Diagnostics
+		planned, diags = prov.providerId(configured)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("provider_id"), planned)...)
+	}
+
+	if !requiresReplace || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("provider_id"), &state)...)
+	if !resp.Diagnostics.HasError() && !state.Equal(planned) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("provider_id"))
+	}
+}
+
+// ValidateConfig records whether the provider block is known to have no
+// default_provider_id, so resources without a provider_id fail validation
+// rather than the plan
+func (p *myScribaeProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var cfg myScribaeProviderConfig
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.setDefaultProviderIdMissing(defaultProviderIdMissing(cfg))
+}
+
+// defaultProviderIdMissing reports whether cfg is known to have no
+// default_provider_id. Unknown values, such as variables during validate, may
+// still set one
+func defaultProviderIdMissing(cfg myScribaeProviderConfig) bool {
+	if cfg.DefaultProviderId.IsUnknown() || cfg.DefaultProviderId.ValueString() != "" || cfg.Profile.IsUnknown() {
+		return false
+	}
+
+	profileName, explicitProfile := credentialsProfileName(cfg)
+	profile, err := loadCredentialsProfile(profileName, explicitProfile)
+	if err != nil {
+		// Configure reports the profile
+		return false
+	}
+
+	return profile == nil || profile.DefaultProviderId == ""
+}
+
+func (p *myScribaeProvider) setDefaultProviderIdMissing(missing bool) {
+	p.defaultProviderIdMu.Lock()
+	defer p.defaultProviderIdMu.Unlock()
+
+	p.defaultProviderIdMissing = missing
+}
+
+// providerIdValidator fails a resource without a provider_id once the
+// provider is known to have no default_provider_id
+type providerIdValidator struct {
+	prov *myScribaeProvider
+}
+
+var _ resource.ConfigValidator = (*providerIdValidator)(nil)
+
+func (v providerIdValidator) Description(ctx context.Context) string {
+	return "provider_id must be set when the provider has no default_provider_id"
+}
+
+func (v providerIdValidator) MarkdownDescription(ctx context.Context) string {
+	return "`provider_id` must be set when the provider has no `default_provider_id`"
+}
+
+func (v providerIdValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if v.prov == nil {
+		return
+	}
+
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("provider_id"), &configured)...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	v.prov.defaultProviderIdMu.Lock()
+	missing := v.prov.defaultProviderIdMissing
+	v.prov.defaultProviderIdMu.Unlock()
+	if missing {
+		resp.Diagnostics.AddAttributeError(path.Root("provider_id"), "missing provider id", missingProviderIdDetail)
+	}
+}
+
+// credentialsProfileName returns the name of the credentials profile to use,
+// and whether it was chosen rather than defaulted
+func credentialsProfileName(cfg myScribaeProviderConfig) (string, bool) {
+	if cfg.Profile.ValueString() != "" {
+		return cfg.Profile.ValueString(), true
+	}
+	if env := os.Getenv(profileEnvVar); env != "" {
+		return env, true
+	}
+
+	return defaultProfile, false
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPlanProviderId(t *testing.T) {
+	const (
+		stateProviderId   = "00000000-0000-0000-0000-000000000001"
+		defaultProviderId = "00000000-0000-0000-0000-000000000002"
+	)
+
+	providerIdSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"provider_id": schema.StringAttribute{Optional: true, Computed: true},
+		},
+	}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"provider_id": tftypes.String}}
+	object := func(providerId tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{"provider_id": providerId})
+	}
+
+	var (
+		unknown   = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+		null      = tftypes.NewValue(tftypes.String, nil)
+		inState   = tftypes.NewValue(tftypes.String, stateProviderId)
+		byDefault = tftypes.NewValue(tftypes.String, defaultProviderId)
+		noState   = tftypes.NewValue(objectType, nil)
+	)
+
+	tests := map[string]struct {
+		configured      tftypes.Value
+		state           tftypes.Value
+		requiresReplace bool
+		wantReplace     bool
+	}{
+		"unknown replaces a known provider": {
+			configured:      unknown,
+			state:           object(inState),
+			requiresReplace: true,
+			wantReplace:     true,
+		},
+		"unknown on create": {
+			configured:      unknown,
+			state:           noState,
+			requiresReplace: true,
+		},
+		"unknown without requires replace": {
+			configured: unknown,
+			state:      object(inState),
+		},
+		"unchanged": {
+			configured:      inState,
+			state:           object(inState),
+			requiresReplace: true,
+		},
+		"changed": {
+			configured:      byDefault,
+			state:           object(inState),
+			requiresReplace: true,
+			wantReplace:     true,
+		},
+		"default replaces another provider": {
+			configured:      null,
+			state:           object(inState),
+			requiresReplace: true,
+			wantReplace:     true,
+		},
+		"default matches state": {
+			configured:      null,
+			state:           object(byDefault),
+			requiresReplace: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: providerIdSchema, Raw: object(test.configured)},
+				State:  tfsdk.State{Schema: providerIdSchema, Raw: test.state},
+				Plan:   tfsdk.Plan{Schema: providerIdSchema, Raw: object(test.configured)},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+
+			planProviderId(context.Background(), req, resp, &myScribaeProvider{DefaultProviderId: defaultProviderId}, test.requiresReplace)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if got := len(resp.RequiresReplace) > 0; got != test.wantReplace {
+				t.Errorf("got requires replace %t, want %t", got, test.wantReplace)
+			}
+		})
+	}
+}
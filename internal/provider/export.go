@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myscribae/myscribae-sdk-go/gql"
+)
+
+// ExportOptions select the providers the export command writes and where
+type ExportOptions struct {
+	// Dir is the directory the .tf files are written to
+	Dir string
+	// ProviderIds are the uuids of the providers to export, defaulting to the
+	// default_provider_id of the credentials profile
+	ProviderIds []string
+	// Profile is the credentials profile to read, as the profile attribute
+	// of the provider block
+	Profile string
+	// Version is the version of the provider binary
+	Version string
+}
+
+// Export writes each provider of opts with its script groups and scripts to
+// a .tf file of its own, with import blocks so that the next terraform apply
+// takes them over rather than creating them. It returns the files written
+func Export(ctx context.Context, opts ExportOptions) ([]string, error) {
+	for _, providerId := range opts.ProviderIds {
+		if !isUuid(providerId) {
+			return nil, fmt.Errorf("invalid provider id %q, expected a uuid", providerId)
+		}
+	}
+
+	// the provider block with no attributes set, so the credentials come
+	// from the environment and the credentials profile
+	cfg := myScribaeProviderConfig{
+		TokenCommand: types.ListNull(types.StringType),
+		ExtraHeaders: types.MapNull(types.StringType),
+	}
+	if opts.Profile != "" {
+		cfg.Profile = types.StringValue(opts.Profile)
+	}
+
+	p := &myScribaeProvider{Version: opts.Version}
+	if err := diagnosticsError(p.configure(ctx, cfg, "")); err != nil {
+		return nil, err
+	}
+
+	providerIds := opts.ProviderIds
+	if len(providerIds) == 0 {
+		if p.DefaultProviderId == "" {
+			return nil, errors.New("no provider to export, the api cannot list the providers of an account so pass -provider-id or set default_provider_id in the credentials profile")
+		}
+		providerIds = []string{p.DefaultProviderId}
+	}
+
+	labels := exportLabels{}
+	var written []string
+	for _, providerId := range providerIds {
+		file, err := p.exportProvider(ctx, opts.Dir, uuid.MustParse(providerId), labels)
+		if err != nil {
+			return written, fmt.Errorf("failed to export provider %s: %w", providerId, err)
+		}
+		written = append(written, file)
+	}
+
+	return written, nil
+}
+
+func (p *myScribaeProvider) exportProvider(ctx context.Context, dir string, providerUuid uuid.UUID, labels exportLabels) (string, error) {
+	prov := p.providerClient(providerUuid)
+
+	profile, err := p.readProvider(ctx, prov)
+	if err != nil {
+		return "", err
+	}
+
+	var catalog catalogQuery
+	if err := prov.Client.Query(ctx, &catalog, map[string]interface{}{
+		"provider_id": prov.ID(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to read script groups and scripts: %w", err)
+	}
+
+	var hcl hclBlocks
+	hcl.comment(fmt.Sprintf("Exported from MyScribae provider %s by terraform-provider-myscribae %s on %s", providerUuid, p.Version, time.Now().UTC().Format(time.DateOnly)))
+
+	providerLabel := labels.next("myscribae_provider", exportName(profile.AltID, profile.Name))
+	providerRef := "myscribae_provider." + providerLabel
+	providerTags, err := p.readProviderTags(ctx, prov)
+	exportTagsError(&hcl, err)
+	hcl.block(`resource "myscribae_provider" "`+providerLabel+`"`, providerAttributes(profile, providerTags)...)
+	exportImport(&hcl, providerRef, providerUuid.String())
+
+	for _, group := range catalog.ProviderSelf.ScriptGroups {
+		groupLabel := labels.next("myscribae_script_group", group.AltID)
+		groupRef := "myscribae_script_group." + groupLabel
+
+		scriptGroup, err := prov.ScriptGroup(group.Uuid.String())
+		if err != nil {
+			return "", err
+		}
+		groupTags, err := p.readScriptGroupTags(ctx, scriptGroup)
+		exportTagsError(&hcl, err)
+
+		attrs := []hclAttribute{
+			{"provider_id", providerRef + ".id"},
+			{"alt_id", hclString(group.AltID)},
+			{"name", hclString(group.Name)},
+			{"description", hclString(group.Description)},
+		}
+		if group.Public {
+			attrs = append(attrs, hclAttribute{"public", hclBool(true)})
+		}
+		attrs = append(attrs, tagsAttributes(groupTags)...)
+		hcl.block(`resource "myscribae_script_group" "`+groupLabel+`"`, attrs...)
+		exportImport(&hcl, groupRef, fmt.Sprintf("%s/%s", providerUuid, group.Uuid))
+
+		for _, script := range group.Scripts {
+			s, err := prov.Script(scriptGroup.AltID, script.Uuid.String())
+			if err != nil {
+				return "", err
+			}
+			scriptTags, err := p.readScriptTags(ctx, s)
+			exportTagsError(&hcl, err)
+
+			scriptLabel := labels.next("myscribae_script", group.AltID+"_"+script.AltID)
+			hcl.block(`resource "myscribae_script" "`+scriptLabel+`"`, scriptAttributes(providerRef, groupRef, script, scriptTags)...)
+			exportImport(&hcl, "myscribae_script."+scriptLabel, fmt.Sprintf("%s/%s/%s", providerUuid, group.Uuid, script.Uuid))
+		}
+	}
+
+	file := filepath.Join(dir, providerLabel+".tf")
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("%s already exists, remove it or export to another directory", file)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(hcl.String()); err != nil {
+		return "", err
+	}
+
+	return file, f.Close()
+}
+
+// providerAttributes leaves out the attributes at their defaults, as a hand
+// written configuration would
+func providerAttributes(profile *gql.ProviderProfile, tags map[string]string) []hclAttribute {
+	var attrs []hclAttribute
+	if profile.AltID != nil && *profile.AltID != "" {
+		attrs = append(attrs, hclAttribute{"alt_id", hclString(*profile.AltID)})
+	}
+	attrs = append(attrs,
+		hclAttribute{"name", hclString(profile.Name)},
+		hclAttribute{"description", hclString(profile.Description)},
+	)
+
+	optional := []struct {
+		name  string
+		value *string
+	}{
+		{"logo_url", profile.LogoUrl},
+		{"banner_url", profile.BannerUrl},
+		{"url", profile.Url},
+		{"color", profile.Color},
+	}
+	for _, o := range optional {
+		if o.value != nil && *o.value != "" && !(o.name == "color" && strings.EqualFold(*o.value, "#A0A0A0")) {
+			attrs = append(attrs, hclAttribute{o.name, hclString(*o.value)})
+		}
+	}
+
+	if profile.Public {
+		attrs = append(attrs, hclAttribute{"public", hclBool(true)})
+	}
+	if profile.AccountService.Enabled {
+		attrs = append(attrs, hclAttribute{"account_service", hclBool(true)})
+	}
+
+	return append(attrs, tagsAttributes(tags)...)
+}
+
+func scriptAttributes(providerRef string, groupRef string, script gql.GQLScriptProfile, tags map[string]string) []hclAttribute {
+	attrs := []hclAttribute{
+		{"provider_id", providerRef + ".id"},
+		{"script_group_id", groupRef + ".id"},
+		{"alt_id", hclString(script.AltID)},
+		{"name", hclString(script.Name)},
+		{"description", hclString(script.Description)},
+		{"recurrence", hclString(script.Recurrence)},
+		{"price_in_cents", hclInt(int64(script.PriceInCents))},
+		{"sla", hclString(exportDuration(int64(script.SlaSec)))},
+		{"token_lifetime", hclString(exportDuration(int64(script.TokenLifetimeSec)))},
+	}
+	if script.Public {
+		attrs = append(attrs, hclAttribute{"public", hclBool(true)})
+	}
+
+	return append(attrs, tagsAttributes(tags)...)
+}
+
+// tagsAttributes exports tags as they were read, which includes any the
+// default_tags of the provider block set. Those can be removed by hand once
+// default_tags are configured
+func tagsAttributes(tags map[string]string) []hclAttribute {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return []hclAttribute{{"tags", hclMap(tags)}}
+}
+
+// exportTagsError notes tags that could not be read in the file, above the
+// block they belong to, rather than failing the export
+func exportTagsError(hcl *hclBlocks, err error) {
+	if err != nil {
+		hcl.comment("the tags could not be read and are left out: " + strings.ReplaceAll(err.Error(), "\n", " "))
+	}
+}
+
+func exportImport(hcl *hclBlocks, to string, id string) {
+	hcl.block("import",
+		hclAttribute{"to", to},
+		hclAttribute{"id", hclString(id)},
+	)
+}
+
+// exportDuration formats sec as the shortest duration, such as 1h30m rather
+// than 1h30m0s
+func exportDuration(sec int64) string {
+	d := (time.Duration(sec) * time.Second).String()
+	if strings.HasSuffix(d, "m0s") {
+		d = strings.TrimSuffix(d, "0s")
+	}
+	if strings.HasSuffix(d, "h0m") {
+		d = strings.TrimSuffix(d, "0m")
+	}
+
+	return d
+}
+
+// exportName prefers the alt id of a provider for its resource name, as it is
+// what identifies it in configuration
+func exportName(altId *string, name string) string {
+	if altId != nil && *altId != "" {
+		return *altId
+	}
+
+	return name
+}
+
+// exportLabels hands out resource names that are unique per resource type
+// across every exported file, as they share one terraform module
+type exportLabels map[string]bool
+
+func (l exportLabels) next(resourceType string, name string) string {
+	base := hclLabel(name)
+	label := base
+	for i := 2; l[resourceType+"."+label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	l[resourceType+"."+label] = true
+
+	return label
+}
+
+// diagnosticsError returns the error diagnostics of diags as one error
+func diagnosticsError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags.Errors() {
+		errs = append(errs, fmt.Errorf("%s: %s", d.Summary(), d.Detail()))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// hclAttribute is an attribute of a block, with its value already rendered
+// as an hcl expression
+type hclAttribute struct {
+	name  string
+	value string
+}
+
+// hclBlocks builds terraform configuration laid out as terraform fmt does
+type hclBlocks struct {
+	b strings.Builder
+}
+
+// comment adds a comment line before the next block
+func (h *hclBlocks) comment(text string) {
+	fmt.Fprintf(&h.b, "# %s\n", text)
+}
+
+// block adds a block, aligning the equals signs of its attributes. Attributes
+// spanning several lines, such as maps, follow the others after a blank line,
+// as terraform fmt only aligns the attributes between them
+func (h *hclBlocks) block(header string, attrs ...hclAttribute) {
+	var single, multi []hclAttribute
+	width := 0
+	for _, attr := range attrs {
+		if strings.Contains(attr.value, "\n") {
+			multi = append(multi, attr)
+			continue
+		}
+		single = append(single, attr)
+		width = max(width, len(attr.name))
+	}
+
+	fmt.Fprintf(&h.b, "%s {\n", header)
+	for _, attr := range single {
+		fmt.Fprintf(&h.b, "  %-*s = %s\n", width, attr.name, attr.value)
+	}
+	for _, attr := range multi {
+		fmt.Fprintf(&h.b, "\n  %s = %s\n", attr.name, attr.value)
+	}
+	h.b.WriteString("}\n\n")
+}
+
+func (h *hclBlocks) String() string {
+	return strings.TrimSuffix(h.b.String(), "\n")
+}
+
+// hclString renders s as a quoted hcl string, escaping template sequences so
+// it is taken literally
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case !unicode.IsPrint(r) && r > 0xffff:
+			fmt.Fprintf(&b, `\U%08x`, r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	quoted := strings.ReplaceAll(b.String(), "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// hclMap renders m as an hcl object of strings for an attribute of a block,
+// with its keys sorted and their equals signs aligned
+func hclMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+
+	keys := make([]string, 0, len(m))
+	width := 0
+	for k := range m {
+		key := hclMapKey(k)
+		keys = append(keys, k)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "    %-*s = %s\n", width, hclMapKey(k), hclString(m[k]))
+	}
+	b.WriteString("  }")
+
+	return b.String()
+}
+
+// hclMapKey leaves a key that is an identifier bare and quotes any other
+func hclMapKey(k string) string {
+	if hclLabel(k) == k {
+		return k
+	}
+
+	return hclString(k)
+}
+
+func hclBool(v bool) string {
+	return strconv.FormatBool(v)
+}
+
+func hclInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// hclLabel turns name into a terraform identifier, such as a resource name
+func hclLabel(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	label := strings.Trim(b.String(), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "_" + label
+	}
+
+	return label
+}
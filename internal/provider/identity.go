@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hasura/go-graphql-client"
+	"github.com/myscribae/myscribae-sdk-go/gql"
+	"github.com/myscribae/myscribae-sdk-go/utilities"
+)
+
+// identityQuery reads who the configured credentials belong to. Neither the
+// sdk nor a published schema has it, so checkCredentials does not depend on it
+type identityQuery struct {
+	Me struct {
+		Uuid           uuid.UUID  `graphql:"uuid"`
+		Email          *string    `graphql:"email"`
+		Name           *string    `graphql:"name"`
+		Scopes         []string   `graphql:"scopes"`
+		TokenExpiresAt *time.Time `graphql:"token_expires_at"`
+	} `graphql:"me"`
+}
+
+// identity is the account behind the configured credentials
+type identity struct {
+	Uuid           uuid.UUID
+	Email          *string
+	Name           *string
+	Scopes         []string
+	TokenExpiresAt *time.Time
+}
+
+func queryIdentity(ctx context.Context, client *graphql.Client, tokens tokenSource) (*identity, error) {
+	var query identityQuery
+	if err := client.Query(ctx, &query, nil); err != nil {
+		return nil, err
+	}
+
+	me := &identity{
+		Uuid:           query.Me.Uuid,
+		Email:          query.Me.Email,
+		Name:           query.Me.Name,
+		Scopes:         query.Me.Scopes,
+		TokenExpiresAt: query.Me.TokenExpiresAt,
+	}
+
+	// the token command may know when its token expires when the api does not
+	if command, ok := tokens.(*commandToken); ok && me.TokenExpiresAt == nil {
+		if expiresAt := command.ExpiresAt(); !expiresAt.IsZero() {
+			me.TokenExpiresAt = &expiresAt
+		}
+	}
+
+	return me, nil
+}
+
+// checkCredentials sends a cheap query with the configured credentials, so
+// rejected ones fail when the provider is configured. It reads the default
+// provider through provider_self, a query of the sdk, when there is one.
+// Otherwise it asks for the identity, and an api without the me query is left
+// to reject bad credentials on the first request that follows
+func checkCredentials(ctx context.Context, client *graphql.Client, tokens tokenSource, defaultProviderId string) error {
+	if defaultProviderId != "" {
+		id, err := utilities.NewAltUuid(defaultProviderId)
+		if err != nil {
+			return err
+		}
+
+		var query gql.GetProviderProfile
+		return client.Query(ctx, &query, map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	_, err := queryIdentity(ctx, client, tokens)
+	if unknownFieldError(err) {
+		tflog.Debug(ctx, "the api has no me query, leaving the credentials to the first request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// splitImportId splits an import id of the form given by format into its
+// parts, such as <provider_id>/<script_group_id> into two
+func splitImportId(id string, format string, resp *resource.ImportStateResponse) ([]string, bool) {
+	want := strings.Count(format, "/") + 1
+	parts := strings.Split(id, "/")
+	if len(parts) != want || slices.Contains(parts, "") {
+		resp.Diagnostics.AddError(
+			"invalid import id",
+			fmt.Sprintf("expected an import id of the form %s, got %q", format, id),
+		)
+		return nil, false
+	}
+
+	return parts, true
+}
+
+// isUuid reports whether id is a uuid rather than an alt id
+func isUuid(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultRequestsPerSecond      = 10
+	defaultMaxConcurrentMutations = 4
+)
+
+// requestLimiter spaces requests to at most requestsPerSecond and lets at most
+// maxConcurrentMutations mutations run at once. It is shared by every
+// resource and data source, so parallel applies stay within the api limits
+type requestLimiter struct {
+	interval  time.Duration
+	mutations chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRequestLimiter(requestsPerSecond int64, maxConcurrentMutations int64) *requestLimiter {
+	return &requestLimiter{
+		interval:  time.Second / time.Duration(requestsPerSecond),
+		mutations: make(chan struct{}, maxConcurrentMutations),
+	}
+}
+
+// wait blocks until a request may be sent and returns a func to call once it
+// has completed
+func (l *requestLimiter) wait(ctx context.Context, mutation bool) (func(), error) {
+	start := time.Now()
+	release := func() {}
+
+	if mutation {
+		select {
+		case l.mutations <- struct{}{}:
+			release = func() { <-l.mutations }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	if queued := time.Since(start); queued >= time.Millisecond {
+		tflog.Debug(ctx, "request queued by the client limiter", map[string]interface{}{
+			"queued":   queued.String(),
+			"mutation": mutation,
+		})
+	}
+
+	return release, nil
+}
+
+// limitedTransport sends every request through a requestLimiter. timeout
+// starts once the limiter lets a request through and covers reading its
+// response body
+type limitedTransport struct {
+	limiter *requestLimiter
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutation, err := isMutation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := t.limiter.wait(req.Context(), mutation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if t.timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels the context of a request once its response body is
+// closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isMutation reports whether req carries a graphql mutation, leaving its body
+// readable and replayable
+func isMutation(req *http.Request) (bool, error) {
+	if req.Body == nil {
+		return false, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return false, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false, nil
+	}
+
+	return strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation"), nil
+}
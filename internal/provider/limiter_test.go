@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newLimitedTestClient sends requests to handler through a limiter that runs
+// one mutation at a time, timing out each request after timeout
+func newLimitedTestClient(t *testing.T, handler http.HandlerFunc, timeout time.Duration) (*http.Client, string) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &http.Client{
+		Transport: &limitedTransport{
+			limiter: newRequestLimiter(1000, 1),
+			timeout: timeout,
+			next:    http.DefaultTransport,
+		},
+	}, server.URL
+}
+
+func postMutation(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(`{"query":"mutation { edit }"}`))
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	return err
+}
+
+func TestLimitedTransportQueueWaitDoesNotCountAgainstTimeout(t *testing.T) {
+	const (
+		requests = 5
+		work     = 40 * time.Millisecond
+		timeout  = 100 * time.Millisecond
+	)
+
+	client, url := newLimitedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(work)
+		io.WriteString(w, `{"data":{}}`)
+	}, timeout)
+
+	// the last mutation waits for the others, about 160ms, longer than the
+	// timeout of each
+	start := time.Now()
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- postMutation(context.Background(), client, url)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if elapsed := time.Since(start); elapsed <= timeout {
+		t.Fatalf("requests took %s, expected the queue to take longer than the timeout of %s", elapsed, timeout)
+	}
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+}
+
+func TestLimitedTransportTimesOutSlowRequests(t *testing.T) {
+	client, url := newLimitedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}, 50*time.Millisecond)
+
+	err := postMutation(context.Background(), client, url)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+}
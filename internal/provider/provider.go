@@ -2,28 +2,79 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hasura/go-graphql-client"
-	"github.com/myscribae/myscribae-sdk-go/gql"
+	"github.com/myscribae/myscribae-sdk-go/environment"
+	"github.com/myscribae/myscribae-terraform-provider/validators"
 )
 
 var _ provider.Provider = (*myScribaeProvider)(nil)
+var _ provider.ProviderWithConfigValidators = (*myScribaeProvider)(nil)
 
 type myScribaeProvider struct {
-	ApiToken string
-	ApiUrl   string
-	Client   *graphql.Client
-	Version  string
+	ApiToken          string
+	ApiUrl            string
+	Client            *graphql.Client
+	CredentialsSource string
+	DefaultProviderId string
+	DefaultTags       map[string]string
+	Version           string
+
+	recurrences []string
+	// recurrencesFailed is set once the recurrences could not be queried, so
+	// they are not queried again
+	recurrencesFailed bool
+	recurrencesMu     sync.Mutex
+
+	// defaultProviderIdMissing is set once the provider block is known to have
+	// no default_provider_id
+	defaultProviderIdMissing bool
+	defaultProviderIdMu      sync.Mutex
+
+	reads readCache
+	creds apiCredentials
 }
 
 type myScribaeProviderConfig struct {
-	ApiToken types.String `tfsdk:"api_token"`
+	ApiToken               types.String       `tfsdk:"api_token"`
+	TokenCommand           types.List         `tfsdk:"token_command"`
+	ApiKey                 types.String       `tfsdk:"api_key"`
+	SecretKey              types.String       `tfsdk:"secret_key"`
+	Profile                types.String       `tfsdk:"profile"`
+	DefaultProviderId      types.String       `tfsdk:"default_provider_id"`
+	RequestsPerSecond      types.Int64        `tfsdk:"requests_per_second"`
+	MaxConcurrentMutations types.Int64        `tfsdk:"max_concurrent_mutations"`
+	ProxyUrl               types.String       `tfsdk:"proxy_url"`
+	CaCertPem              types.String       `tfsdk:"ca_cert_pem"`
+	CaCertFile             types.String       `tfsdk:"ca_cert_file"`
+	ClientCert             types.String       `tfsdk:"client_cert"`
+	ClientKey              types.String       `tfsdk:"client_key"`
+	InsecureSkipVerify     types.Bool         `tfsdk:"insecure_skip_verify"`
+	ExtraHeaders           types.Map          `tfsdk:"extra_headers"`
+	UserAgentSuffix        types.String       `tfsdk:"user_agent_suffix"`
+	DefaultTags            *defaultTagsConfig `tfsdk:"default_tags"`
+}
+
+type defaultTagsConfig struct {
+	Tags types.Map `tfsdk:"tags"`
 }
 
 func New(version string) func() provider.Provider {
@@ -35,8 +86,6 @@ func New(version string) func() provider.Provider {
 }
 
 func (p *myScribaeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	apiUrl := os.Getenv("MYSCRIBAE_API_URL")
-	apiToken := os.Getenv("MYSCRIBAE_API_TOKEN")
 	var cfg myScribaeProviderConfig
 
 	diags := req.Config.Get(ctx, &cfg)
@@ -45,23 +94,220 @@ func (p *myScribaeProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	if cfg.ApiToken.ValueString() != "" {
-		apiToken = cfg.ApiToken.ValueString()
+	resp.Diagnostics.Append(p.configure(ctx, cfg, req.TerraformVersion)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.DataSourceData = p
+	resp.ResourceData = p
+}
+
+// configure resolves the credentials and builds the api client from cfg. It
+// is shared by Configure and the export command, which has no terraform
+// configuration
+func (p *myScribaeProvider) configure(ctx context.Context, cfg myScribaeProviderConfig, terraformVersion string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	profileName, explicitProfile := credentialsProfileName(cfg)
+	profile, err := loadCredentialsProfile(profileName, explicitProfile)
+	if err != nil {
+		diags.AddAttributeError(path.Root("profile"), "failed to read credentials profile", err.Error())
+		return diags
 	}
 
-	if apiUrl == "" {
-		apiUrl = "https://api.myscribae.com"
+	var tokenCommand []string
+	diags.Append(cfg.TokenCommand.ElementsAs(ctx, &tokenCommand, false)...)
+	if diags.HasError() {
+		return diags
 	}
 
-	p.ApiUrl = apiUrl
-	p.ApiToken = apiToken
-	p.Client = gql.CreateGraphQLClient(
+	// the credentials come from the api_token, token_command or api_key
+	// attributes, then a profile selected with the profile attribute or
+	// MYSCRIBAE_PROFILE, then the environment, then the default profile
+	var (
+		creds             apiCredentials
+		credentialsSource string
+	)
+	profileCreds, profileHasCreds := profile.credentials()
+	switch {
+	case cfg.ApiToken.ValueString() != "":
+		creds.tokens, credentialsSource = staticToken(cfg.ApiToken.ValueString()), "the api_token attribute"
+	case len(tokenCommand) > 0:
+		creds.tokens, credentialsSource = newCommandToken(tokenCommand), fmt.Sprintf("the token_command %q", tokenCommand[0])
+	case cfg.ApiKey.ValueString() != "":
+		creds.apiKey, creds.secretKey, credentialsSource = cfg.ApiKey.ValueString(), cfg.SecretKey.ValueString(), "the api_key and secret_key attributes"
+	case explicitProfile && profileHasCreds:
+		creds, credentialsSource = profileCreds, profile.source()
+	case os.Getenv(apiTokenEnvVar) != "":
+		creds.tokens, credentialsSource = staticToken(os.Getenv(apiTokenEnvVar)), fmt.Sprintf("the %s environment variable", apiTokenEnvVar)
+	case os.Getenv(environment.ApiKeyEnvVar) != "" && os.Getenv(environment.SecretKeyEnvVar) != "":
+		creds.apiKey, creds.secretKey = os.Getenv(environment.ApiKeyEnvVar), os.Getenv(environment.SecretKeyEnvVar)
+		credentialsSource = fmt.Sprintf("the %s and %s environment variables", environment.ApiKeyEnvVar, environment.SecretKeyEnvVar)
+	case profileHasCreds:
+		creds, credentialsSource = profileCreds, profile.source()
+	default:
+		file, _ := credentialsFile()
+		diags.AddAttributeError(
+			path.Root("api_token"),
+			"missing api credentials",
+			fmt.Sprintf(
+				"No api token or api key pair was found. Set the api_token, token_command or api_key and secret_key attributes, the %s or %s and %s environment variables, or api_token or api_key and secret_key in profile %q of %s.",
+				apiTokenEnvVar,
+				environment.ApiKeyEnvVar,
+				environment.SecretKeyEnvVar,
+				profileName,
+				file,
+			),
+		)
+		return diags
+	}
+
+	var apiToken string
+	if creds.tokens != nil {
+		if apiToken, err = creds.tokens.Token(ctx); err != nil {
+			diags.AddError(
+				"failed to get api token",
+				fmt.Sprintf("Getting the api token from %s failed: %s", credentialsSource, err),
+			)
+			return diags
+		}
+	}
+
+	apiUrl := defaultApiUrl
+	switch {
+	case explicitProfile && profile != nil && profile.ApiUrl != "":
+		apiUrl = profile.ApiUrl
+	case os.Getenv(apiUrlEnvVar) != "":
+		apiUrl = os.Getenv(apiUrlEnvVar)
+	case profile != nil && profile.ApiUrl != "":
+		apiUrl = profile.ApiUrl
+	}
+
+	defaultProviderId := cfg.DefaultProviderId.ValueString()
+	if defaultProviderId == "" && profile != nil {
+		defaultProviderId = profile.DefaultProviderId
+	}
+
+	var defaultTags map[string]string
+	if cfg.DefaultTags != nil {
+		if cfg.DefaultTags.Tags.IsUnknown() {
+			diags.AddAttributeError(
+				path.Root("default_tags").AtName("tags"),
+				"unknown default tags",
+				"default_tags must be known when planning, as every resource merges them into its tags_all. Set them from variables or locals rather than from resource attributes.",
+			)
+			return diags
+		}
+		diags.Append(cfg.DefaultTags.Tags.ElementsAs(ctx, &defaultTags, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	tflog.Info(ctx, "configured MyScribae credentials", map[string]interface{}{
+		"source":  credentialsSource,
+		"api_url": apiUrl,
+	})
+
+	requestsPerSecond := int64(defaultRequestsPerSecond)
+	if !cfg.RequestsPerSecond.IsNull() {
+		requestsPerSecond = cfg.RequestsPerSecond.ValueInt64()
+	}
+
+	maxConcurrentMutations := int64(defaultMaxConcurrentMutations)
+	if !cfg.MaxConcurrentMutations.IsNull() {
+		maxConcurrentMutations = cfg.MaxConcurrentMutations.ValueInt64()
+	}
+
+	transport, err := newHttpTransport(transportOptions{
+		ProxyUrl:           cfg.ProxyUrl.ValueString(),
+		CaCertPem:          cfg.CaCertPem.ValueString(),
+		CaCertFile:         cfg.CaCertFile.ValueString(),
+		ClientCert:         cfg.ClientCert.ValueString(),
+		ClientKey:          cfg.ClientKey.ValueString(),
+		InsecureSkipVerify: cfg.InsecureSkipVerify.ValueBool(),
+	})
+	if err != nil {
+		diags.AddError("failed to configure the http transport", err.Error())
+		return diags
+	}
+	if cfg.InsecureSkipVerify.ValueBool() {
+		diags.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"tls verification is disabled",
+			fmt.Sprintf("insecure_skip_verify is set, so the certificate of %s is not verified and anyone on the network path can read and change the requests, including the api credentials. Trust a private certificate authority with ca_cert_pem or ca_cert_file instead.", apiUrl),
+		)
+		tflog.Warn(ctx, "tls verification of the MyScribae API is disabled", map[string]interface{}{
+			"api_url": apiUrl,
+		})
+	}
+
+	var extraHeaders map[string]string
+	diags.Append(cfg.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	headers := map[string]string{
+		userAgentHeader: userAgent(p.Version, terraformVersion, cfg.UserAgentSuffix.ValueString()),
+	}
+	for name, value := range extraHeaders {
+		headers[name] = value
+	}
+
+	client := newApiClient(
 		apiUrl,
-		&apiToken,
+		creds,
+		newRequestLimiter(requestsPerSecond, maxConcurrentMutations),
+		transport,
+		headers,
 	)
 
-	resp.DataSourceData = p
-	resp.ResourceData = p
+	// a cheap query, so bad credentials fail here rather than on the first
+	// resource. Other failures may be transient and are left to the requests
+	// that follow
+	var unauthorized *unauthorizedError
+	if err := checkCredentials(ctx, client, creds.tokens, defaultProviderId); errors.As(err, &unauthorized) {
+		diags.AddError(
+			"invalid api credentials",
+			fmt.Sprintf(
+				"The MyScribae API at %s rejected the credentials from %s (%s).\n\nCheck that they are current and belong to this api_url, or set other credentials on the myscribae provider block.",
+				apiUrl,
+				credentialsSource,
+				unauthorized.reason(),
+			),
+		)
+		return diags
+	} else if err != nil {
+		tflog.Warn(ctx, "failed to check the MyScribae credentials", map[string]interface{}{
+			"source": credentialsSource,
+			"error":  err.Error(),
+		})
+	}
+
+	// the source is easily shadowed by the environment or a profile, so it
+	// is shown rather than only logged
+	diags.AddWarning(
+		fmt.Sprintf("using MyScribae credentials from %s", credentialsSource),
+		fmt.Sprintf(
+			"The myscribae provider authenticates with %s against %s. Credentials are taken from the api_token, token_command or api_key attributes, then the profile selected with the profile attribute or %s, then the environment, then the %s profile.",
+			credentialsSource,
+			apiUrl,
+			profileEnvVar,
+			defaultProfile,
+		),
+	)
+
+	p.ApiUrl = apiUrl
+	p.ApiToken = apiToken
+	p.creds = creds
+	p.CredentialsSource = credentialsSource
+	p.DefaultProviderId = defaultProviderId
+	p.setDefaultProviderIdMissing(defaultProviderId == "")
+	p.DefaultTags = defaultTags
+	p.Client = client
+
+	return diags
 }
 
 func (p *myScribaeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -73,25 +319,158 @@ func (p *myScribaeProvider) DataSources(ctx context.Context) []func() datasource
 		newProviderDataSource,
 		newScriptGroupDataSource,
 		newScriptDataSource,
+		newCatalogManifestDataSource,
+		newCurrentIdentityDataSource,
 	}
 }
 
 func (p *myScribaeProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		newProviderResource,
-		newScriptGroupResource,
-		newScriptResource,
+		func() resource.Resource { return newScriptGroupResource(p) },
+		func() resource.Resource { return newScriptResource(p) },
+		func() resource.Resource { return newCatalogResource(p) },
 	}
 }
 
 func (p *myScribaeProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages the providers, script groups and scripts of the MyScribae API.\n\n" +
+			"Requests authenticate with an api token, or with the api_key and secret_key pair of a MyScribae provider. " +
+			"Exchanging a key pair for refreshed bearer tokens is not supported, as neither the MyScribae sdk nor the api offers such an exchange. The pair is sent as is with every request, is never refreshed and does not expire during an apply. " +
+			"Only a token from token_command is refreshed: it is requested again a minute before its expires_at, and a request the api rejects with 401 is retried once with a fresh token from the command. " +
+			"A static api_token, from the attribute, the environment or the credentials profile, is never refreshed, so it must outlive the apply. Use token_command for applies that outlive a short-lived token.",
 		Attributes: map[string]schema.Attribute{
 			"api_token": schema.StringAttribute{
-				Description: "You must provide an API token to authenticate with the MyScribae API",
-				Required:    true,
+				Description: "The API token to authenticate with the MyScribae API. Falls back to MYSCRIBAE_API_TOKEN, then to api_token in the credentials profile",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_command": schema.ListAttribute{
+				Description: "A command and its arguments printing a json object with the api token and when it expires, as {\"token\": \"...\", \"expires_at\": \"2006-01-02T15:04:05Z\"}. The command runs again once the token expires",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"api_key": schema.StringAttribute{
+				Description: "The api key of a MyScribae provider to authenticate with instead of an api token, together with secret_key. The pair is sent with every request rather than exchanged for a token. Falls back to MYSCRIBAE_API_KEY, then to api_key in the credentials profile",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"secret_key": schema.StringAttribute{
+				Description: "The secret key paired with api_key. Falls back to MYSCRIBAE_SECRET_KEY, then to secret_key in the credentials profile",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "The profile of ~/.myscribae/credentials to read api_url, api_token, api_key, secret_key and default_provider_id from. Falls back to MYSCRIBAE_PROFILE, then to the default profile. A profile selected here or with MYSCRIBAE_PROFILE takes precedence over the MYSCRIBAE_API_URL, MYSCRIBAE_API_TOKEN, MYSCRIBAE_API_KEY and MYSCRIBAE_SECRET_KEY environment variables",
+				Optional:    true,
+			},
+			"default_provider_id": schema.StringAttribute{
+				Description: "The provider id used by resources and data sources that do not set provider_id. Falls back to default_provider_id in the credentials profile",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.NewUuidValidator(false),
+				},
+			},
+			"requests_per_second": schema.Int64Attribute{
+				Description: fmt.Sprintf("The most requests sent to the MyScribae API per second, across all resources and data sources (default %d)", defaultRequestsPerSecond),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_mutations": schema.Int64Attribute{
+				Description: fmt.Sprintf("The most mutations sent to the MyScribae API at once, whatever the terraform parallelism (default %d)", defaultMaxConcurrentMutations),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "The url of the proxy to reach the MyScribae API through. Falls back to HTTPS_PROXY, HTTP_PROXY and NO_PROXY",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.NewUrlValidator(false, validators.AllowSchemes("http", "https", "socks5")),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded certificates of a private certificate authority to trust, such as that of an intercepting proxy, as well as the system ones",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "The path of a file with PEM encoded certificates to trust, as for ca_cert_pem",
+				Optional:    true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: "The PEM encoded client certificate for mutual tls, together with client_key. Use file() to read it from disk",
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: "The PEM encoded private key of client_cert",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Do not verify the tls certificate of the MyScribae API. Only meant for debugging, as it exposes the api credentials to anyone on the network path (default false)",
+				Optional:    true,
+			},
+			"extra_headers": schema.MapAttribute{
+				Description: "Headers added to every request to the MyScribae API, such as those a gateway in front of it requires. They cannot replace the authentication headers or the User-Agent, see user_agent_suffix",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.LengthAtLeast(1),
+						stringvalidator.NoneOfCaseInsensitive(apiTokenHeader, apiKeyHeader, secretKeyHeader, userAgentHeader),
+					),
+				},
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Description: fmt.Sprintf("Appended to the User-Agent sent with every request, terraform-provider-myscribae/<version> terraform/<version>, after %s", appendUserAgentEnvVar),
+				Optional:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"default_tags": schema.SingleNestedBlock{
+				Description: "Tags merged into the tags of every provider, script group and script. The tags of a resource win over these. Tags use a tags field of the api that the sdk does not model, so resources without tags only warn when it cannot be read",
+				Attributes: map[string]schema.Attribute{
+					"tags": schema.MapAttribute{
+						Description: "The tags to merge into the tags of every resource",
+						ElementType: types.StringType,
+						Optional:    true,
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *myScribaeProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("api_token"),
+			path.MatchRoot("token_command"),
+			path.MatchRoot("api_key"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("secret_key"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("ca_cert_pem"),
+			path.MatchRoot("ca_cert_file"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("client_cert"),
+			path.MatchRoot("client_key"),
+		),
 	}
 }
 
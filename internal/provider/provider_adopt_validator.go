@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// providerAdoptValidator makes taking over an existing provider explicit: a
+// uuid is only accepted together with adopt_existing, and the other way round
+type providerAdoptValidator struct{}
+
+var _ resource.ConfigValidator = (*providerAdoptValidator)(nil)
+
+func (v providerAdoptValidator) Description(ctx context.Context) string {
+	return "uuid and adopt_existing must be set together"
+}
+
+func (v providerAdoptValidator) MarkdownDescription(ctx context.Context) string {
+	return "`uuid` and `adopt_existing` must be set together"
+}
+
+func (v providerAdoptValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var (
+		providerUuid  types.String
+		adoptExisting types.Bool
+	)
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("uuid"), &providerUuid)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if resp.Diagnostics.HasError() || providerUuid.IsUnknown() || adoptExisting.IsUnknown() {
+		return
+	}
+
+	hasUuid := providerUuid.ValueString() != ""
+	if hasUuid && !adoptExisting.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("uuid"),
+			"uuid requires adopt_existing",
+			"Setting uuid takes over an existing provider. Set adopt_existing = true to confirm, or remove uuid to create a new provider.",
+		)
+	}
+
+	if !hasUuid && adoptExisting.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("adopt_existing"),
+			"adopt_existing requires uuid",
+			"Set uuid to the provider to take over.",
+		)
+	}
+}
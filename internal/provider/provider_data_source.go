@@ -2,13 +2,14 @@ package provider
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
-	sdk "github.com/myscribae/myscribae-sdk-go"
 	"github.com/myscribae/myscribae-sdk-go/provider"
 )
 
@@ -19,6 +20,24 @@ type mysribaeProviderDataSource struct {
 
 var _ datasource.DataSource = (*mysribaeProviderDataSource)(nil)
 
+type myscribaeProviderDataSourceData struct {
+	Id             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	AltID          types.String `tfsdk:"alt_id"`
+	Uuid           types.String `tfsdk:"uuid"`
+	Description    types.String `tfsdk:"description"`
+	LogoUrl        types.String `tfsdk:"logo_url"`
+	BannerUrl      types.String `tfsdk:"banner_url"`
+	Url            types.String `tfsdk:"url"`
+	Color          types.String `tfsdk:"color"`
+	Public         types.Bool   `tfsdk:"public"`
+	AccountService types.Bool   `tfsdk:"account_service"`
+	SecretKey      types.String `tfsdk:"secret_key"`
+	ApiKey         types.String `tfsdk:"api_key"`
+	Tags           types.Map    `tfsdk:"tags"`
+	TagsAll        types.Map    `tfsdk:"tags_all"`
+}
+
 func newProviderDataSource() datasource.DataSource {
 	return &mysribaeProviderDataSource{}
 }
@@ -88,6 +107,16 @@ func (e *mysribaeProviderDataSource) Schema(ctx context.Context, req datasource.
 				Description: "Is the provider an account service",
 				Computed:    true,
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the provider",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "All tags of the provider. The same as tags, as data sources do not merge default_tags",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 			"secret_key": schema.StringAttribute{
 				Description: "The secret key of the provider",
 				Optional:    true,
@@ -123,7 +152,7 @@ func (e *mysribaeProviderDataSource) MakeClient(
 
 func (e *mysribaeProviderDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var (
-		data myscribaeProviderResourceData
+		data myscribaeProviderDataSourceData
 		err  error
 	)
 
@@ -132,14 +161,22 @@ func (e *mysribaeProviderDataSource) Read(ctx context.Context, req datasource.Re
 		return
 	}
 
-	e.myscribaeProvider, err = sdk.NewProvider(provider.ProviderConfig{
-		ApiToken:  &e.terraformProvider.ApiToken,
+	// the shared client, so the read goes through the limiter and transport
+	// options of the provider block, sending the keys given here as the sdk
+	// does
+	client := e.terraformProvider.Client
+	if apiKey := data.ApiKey.ValueString(); apiKey != "" {
+		secretKey := data.SecretKey.ValueString()
+		client = client.WithRequestModifier(func(r *http.Request) {
+			r.Header.Set(apiKeyHeader, apiKey)
+			r.Header.Set(secretKeyHeader, secretKey)
+		})
+	}
+	e.myscribaeProvider = &provider.Provider{
+		ApiUrl:    e.terraformProvider.ApiUrl,
 		ApiKey:    data.ApiKey.ValueStringPointer(),
 		SecretKey: data.SecretKey.ValueStringPointer(),
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("error creating provider client", err.Error())
-		return
+		Client:    client,
 	}
 
 	profile, err := e.myscribaeProvider.Read(ctx)
@@ -148,7 +185,14 @@ func (e *mysribaeProviderDataSource) Read(ctx context.Context, req datasource.Re
 		return
 	}
 
-	state := myscribaeProviderResourceData{
+	remoteTags, err := e.terraformProvider.readProviderTags(ctx, e.myscribaeProvider)
+	tags, diags := dataSourceTags(ctx, remoteTags, err)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	state := myscribaeProviderDataSourceData{
 		SecretKey:      data.SecretKey,
 		ApiKey:         data.ApiKey,
 		Id:             basetypes.NewStringValue(profile.Uuid.String()),
@@ -162,9 +206,11 @@ func (e *mysribaeProviderDataSource) Read(ctx context.Context, req datasource.Re
 		Color:          basetypes.NewStringPointerValue(profile.Color),
 		Public:         basetypes.NewBoolValue(profile.Public),
 		AccountService: basetypes.NewBoolValue(profile.AccountService.Enabled),
+		Tags:           tags,
+		TagsAll:        tags,
 	}
 
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
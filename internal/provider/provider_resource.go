@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -21,6 +24,17 @@ import (
 
 var _ resource.Resource = (*myscribaeProviderResource)(nil)
 var _ resource.ResourceWithConfigure = (*myscribaeProviderResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*myscribaeProviderResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*myscribaeProviderResource)(nil)
+var _ resource.ResourceWithImportState = (*myscribaeProviderResource)(nil)
+
+const (
+	// keyManagementPreserve leaves the keys of an adopted provider untouched
+	keyManagementPreserve = "preserve"
+	// keyManagementReset rotates the keys when a provider is adopted, or when
+	// key_management changes to reset
+	keyManagementReset = "reset"
+)
 
 type myscribaeProviderResource struct {
 	terraformProvider *myScribaeProvider
@@ -39,8 +53,12 @@ type myscribaeProviderResourceData struct {
 	Color          types.String `tfsdk:"color"`
 	Public         types.Bool   `tfsdk:"public"`
 	AccountService types.Bool   `tfsdk:"account_service"`
+	AdoptExisting  types.Bool   `tfsdk:"adopt_existing"`
+	KeyManagement  types.String `tfsdk:"key_management"`
 	SecretKey      types.String `tfsdk:"secret_key"`
 	ApiKey         types.String `tfsdk:"api_key"`
+	Tags           types.Map    `tfsdk:"tags"`
+	TagsAll        types.Map    `tfsdk:"tags_all"`
 }
 
 func newProviderResource() resource.Resource {
@@ -66,6 +84,7 @@ func (e *myscribaeProviderResource) Configure(ctx context.Context, req resource.
 
 func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The id of the provider",
@@ -83,8 +102,9 @@ func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.Sch
 				PlanModifiers: []planmodifier.String{},
 			},
 			"uuid": schema.StringAttribute{
-				Description: "The uuid of the provider",
+				Description: "The uuid of the provider. Set it together with adopt_existing to take over an existing provider",
 				Optional:    true,
+				Computed:    true,
 				Validators: []validator.String{
 					validators.NewUuidValidator(false),
 				},
@@ -93,6 +113,21 @@ func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.Sch
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over the existing provider given by uuid instead of creating a new one",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"key_management": schema.StringAttribute{
+				Description: "What to do with the api and secret keys of the provider: preserve leaves the keys of an adopted provider untouched, reset rotates them when the provider is adopted or when this changes to reset",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(keyManagementPreserve),
+				Validators: []validator.String{
+					stringvalidator.OneOf(keyManagementPreserve, keyManagementReset),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the provider",
 				Required:    true,
@@ -108,27 +143,27 @@ func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.Sch
 				},
 			},
 			"logo_url": schema.StringAttribute{
-				Description: "The logo url of the provider",
+				Description: "The logo url of the provider (https, or the deprecated http, not on a local host such as localhost, ending in .png, .jpg, .jpeg, .gif, .svg or .webp)",
 				Optional:    true,
 				Required:    false,
 				Validators: []validator.String{
-					validators.NewUrlValidator(false),
+					validators.NewUrlValidator(false, validators.DenyHosts(validators.LocalUrlHosts...), validators.RequireImageExtension()),
 				},
 			},
 			"banner_url": schema.StringAttribute{
-				Description: "The banner url of the provider",
+				Description: "The banner url of the provider (https, or the deprecated http, not on a local host such as localhost, ending in .png, .jpg, .jpeg, .gif, .svg or .webp)",
 				Optional:    true,
 				Required:    false,
 				Validators: []validator.String{
-					validators.NewUrlValidator(false),
+					validators.NewUrlValidator(false, validators.DenyHosts(validators.LocalUrlHosts...), validators.RequireImageExtension()),
 				},
 			},
 			"url": schema.StringAttribute{
-				Description: "The url of the provider",
+				Description: "The url of the provider (https, or the deprecated http, not on a local host such as localhost)",
 				Optional:    true,
 				Required:    false,
 				Validators: []validator.String{
-					validators.NewUrlValidator(false),
+					validators.NewUrlValidator(false, validators.DenyHosts(validators.LocalUrlHosts...)),
 				},
 			},
 			"color": schema.StringAttribute{
@@ -152,6 +187,19 @@ func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.Sch
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the provider, such as its owner team, cost center or environment",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "The tags of the provider merged over the default_tags of the myscribae provider block, as stored by the api",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 			"secret_key": schema.StringAttribute{
 				Description: "The secret key of the provider",
 				Computed:    true,
@@ -172,12 +220,73 @@ func (e *myscribaeProviderResource) Schema(ctx context.Context, req resource.Sch
 	}
 }
 
+func (e *myscribaeProviderResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		providerAdoptValidator{},
+	}
+}
+
+func (e *myscribaeProviderResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planTagsAll(ctx, req, resp, e.terraformProvider)
+
+	planData := myscribaeProviderResourceData{}
+	if diags := req.Plan.Get(ctx, &planData); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	rotate := false
+	if req.State.Raw.IsNull() {
+		if !planData.AdoptExisting.ValueBool() {
+			return
+		}
+
+		if planData.KeyManagement.ValueString() != keyManagementReset {
+			// the keys of an adopted provider are never read back
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("api_key"), types.StringNull())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secret_key"), types.StringNull())...)
+			return
+		}
+		rotate = true
+	} else {
+		var stateKeyManagement types.String
+		if diags := req.State.GetAttribute(ctx, path.Root("key_management"), &stateKeyManagement); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		rotate = planData.KeyManagement.ValueString() == keyManagementReset &&
+			stateKeyManagement.ValueString() != keyManagementReset
+	}
+
+	if !rotate {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("api_key"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secret_key"), types.StringUnknown())...)
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("key_management"),
+		"provider keys will be rotated",
+		fmt.Sprintf(
+			"Applying this plan resets the api and secret keys of provider %s. Every service using the current keys will fail to authenticate until it is given the new keys. Set key_management = %q to keep the current keys.",
+			planData.Uuid.ValueString(),
+			keyManagementPreserve,
+		),
+	)
+}
+
 func (e *myscribaeProviderResource) MakeClient(ctx context.Context, providerId string) error {
 	providerUuid, err := uuid.Parse(providerId)
 	if err != nil {
 		return err
 	}
 
+	// not the shared client from providerClient, as resetting the keys
+	// stores them on the client
 	e.myscribaeProvider = &provider.Provider{
 		Uuid:   providerUuid,
 		Client: e.terraformProvider.Client,
@@ -187,18 +296,19 @@ func (e *myscribaeProviderResource) MakeClient(ctx context.Context, providerId s
 }
 
 func (e *myscribaeProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	planData := myscribaeProviderResourceData{}
 	if diags := req.Plan.Get(ctx, &planData); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	// if plan has uuid, then we just take over this provider
-	// if plan does not have uuid, then we attempt to create one
-	// with this provider
+	// if adopt_existing is set, then we just take over the provider in uuid
+	// otherwise we create a new provider
 
 	var err error
-	if planData.Uuid.IsNull() {
+	if !planData.AdoptExisting.ValueBool() {
 		// create a new provider
 		e.myscribaeProvider, err = provider.CreateNewProvider(
 			ctx,
@@ -259,15 +369,17 @@ func (e *myscribaeProviderResource) Create(ctx context.Context, req resource.Cre
 		planData.Id = basetypes.NewStringValue(provUuid.String())
 		planData.Uuid = basetypes.NewStringValue(provUuid.String())
 
-		// if we do not have a secret key, which likely, then we must update the secret key and keep it in state
-		// this is a one time operation, unless the secret key needs to be reset
-		err = e.myscribaeProvider.ResetProviderKeys(ctx)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"failed to reset provider keys",
-				err.Error(),
-			)
-			return
+		// the keys of an adopted provider are in use elsewhere, so they are
+		// only reset when explicitly asked for
+		if planData.KeyManagement.ValueString() == keyManagementReset {
+			err = e.myscribaeProvider.ResetProviderKeys(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"failed to reset provider keys",
+					err.Error(),
+				)
+				return
+			}
 		}
 	}
 
@@ -291,11 +403,43 @@ func (e *myscribaeProviderResource) Create(ctx context.Context, req resource.Cre
 		Color:          planData.Color,
 		Public:         planData.Public,
 		AccountService: planData.AccountService,
+		AdoptExisting:  planData.AdoptExisting,
+		KeyManagement:  planData.KeyManagement,
 		SecretKey:      basetypes.NewStringPointerValue(e.myscribaeProvider.SecretKey),
 		ApiKey:         basetypes.NewStringPointerValue(e.myscribaeProvider.ApiKey),
+		Tags:           types.MapNull(types.StringType),
+		TagsAll:        types.MapNull(types.StringType),
+	}
+
+	// the provider exists from here on, and its keys are only known now, so
+	// it is saved before the tags are set
+	if diags := resp.State.Set(ctx, state); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// the tags of an adopted provider are left untouched unless tags or
+	// default_tags set any
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, planData.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.IsNull() {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setProviderTags(ctx, e.myscribaeProvider, tags); err != nil {
+			tagsNotSetWarning(&resp.Diagnostics, "provider", err)
+		}
 	}
 
-	diags := resp.State.Set(ctx, state)
+	state.Tags = planData.Tags
+	state.TagsAll = tagsAll
+
+	diags = resp.State.Set(ctx, state)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
@@ -317,7 +461,7 @@ func (e *myscribaeProviderResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	profile, err := e.myscribaeProvider.Read(ctx)
+	profile, err := e.terraformProvider.readProvider(ctx, e.myscribaeProvider)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"failed to get provider profile",
@@ -340,6 +484,15 @@ func (e *myscribaeProviderResource) Read(ctx context.Context, req resource.ReadR
 		Color:          basetypes.NewStringPointerValue(profile.Color),
 		Public:         basetypes.NewBoolValue(profile.Public),
 		AccountService: basetypes.NewBoolValue(profile.AccountService.Enabled),
+		AdoptExisting:  currentState.AdoptExisting,
+		KeyManagement:  currentState.KeyManagement,
+	}
+
+	newState.Tags, newState.TagsAll = e.terraformProvider.readResourceTags(ctx, "provider", func(ctx context.Context) (map[string]string, error) {
+		return e.terraformProvider.readProviderTags(ctx, e.myscribaeProvider)
+	}, currentState.Tags, currentState.TagsAll, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	if d := resp.State.Set(ctx, &newState); d.HasError() {
@@ -347,7 +500,26 @@ func (e *myscribaeProviderResource) Read(ctx context.Context, req resource.ReadR
 	}
 }
 
+// ImportState imports the provider given by its uuid as if terraform had
+// created it, so its configuration needs neither uuid nor adopt_existing. Its
+// keys are never read back, so they stay unset until key_management resets
+// them
+func (e *myscribaeProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !isUuid(req.ID) {
+		resp.Diagnostics.AddError("invalid import id", fmt.Sprintf("expected the uuid of the provider, got %q", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key_management"), keyManagementPreserve)...)
+	markImported(ctx, resp)
+}
+
 func (e *myscribaeProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	currentState := myscribaeProviderResourceData{}
 	if diags := req.State.Get(ctx, &currentState); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -388,11 +560,42 @@ func (e *myscribaeProviderResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, planData.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.Equal(currentState.TagsAll) {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setProviderTags(ctx, e.myscribaeProvider, tags); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("tags"), "failed to set provider tags", err.Error())
+			return
+		}
+	}
+
+	secretKey, apiKey := currentState.SecretKey, currentState.ApiKey
+	if planData.KeyManagement.ValueString() == keyManagementReset &&
+		currentState.KeyManagement.ValueString() != keyManagementReset {
+		if err := e.myscribaeProvider.ResetProviderKeys(ctx); err != nil {
+			resp.Diagnostics.AddError(
+				"failed to reset provider keys",
+				err.Error(),
+			)
+			return
+		}
+		secretKey = basetypes.NewStringPointerValue(e.myscribaeProvider.SecretKey)
+		apiKey = basetypes.NewStringPointerValue(e.myscribaeProvider.ApiKey)
+	}
+
 	planData.Id = basetypes.NewStringValue(resultUuid.String())
 	planData.Uuid = basetypes.NewStringValue(resultUuid.String())
 	newState := myscribaeProviderResourceData{
-		SecretKey:      currentState.SecretKey,
-		ApiKey:         currentState.ApiKey,
+		SecretKey:      secretKey,
+		ApiKey:         apiKey,
 		Id:             basetypes.NewStringValue(resultUuid.String()),
 		Uuid:           basetypes.NewStringValue(resultUuid.String()),
 		Name:           planData.Name,
@@ -404,9 +607,13 @@ func (e *myscribaeProviderResource) Update(ctx context.Context, req resource.Upd
 		Color:          planData.Color,
 		Public:         planData.Public,
 		AccountService: planData.AccountService,
+		AdoptExisting:  planData.AdoptExisting,
+		KeyManagement:  planData.KeyManagement,
+		Tags:           planData.Tags,
+		TagsAll:        tagsAll,
 	}
 
-	diags := resp.State.Set(ctx, &newState)
+	diags = resp.State.Set(ctx, &newState)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
@@ -414,6 +621,8 @@ func (e *myscribaeProviderResource) Update(ctx context.Context, req resource.Upd
 }
 
 func (e *myscribaeProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	currentState := myscribaeProviderResourceData{}
 	if diags := req.State.Get(ctx, &currentState); diags != nil {
 		resp.Diagnostics.Append(diags...)
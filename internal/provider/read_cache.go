@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/myscribae/myscribae-sdk-go/gql"
+	"github.com/myscribae/myscribae-sdk-go/provider"
+)
+
+// readCache memoizes reads for the lifetime of the provider process, which
+// terraform starts once per command. Concurrent reads of the same object share
+// one query, failed reads are not kept and every mutation clears the cache
+type readCache struct {
+	mu        sync.Mutex
+	entries   map[string]*readCacheEntry
+	providers map[uuid.UUID]*provider.Provider
+
+	// catalogUnsupported is set once the api reports the catalog query unknown
+	catalogUnsupported bool
+}
+
+type readCacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// cachedRead returns the cached result of key, calling read when there is none
+func cachedRead[T any](ctx context.Context, c *readCache, key string, read func(context.Context) (*T, error)) (*T, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*readCacheEntry{}
+	}
+
+	entry, ok := c.entries[key]
+	if ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		tflog.Debug(ctx, "read cache hit", map[string]interface{}{"key": key})
+	} else {
+		entry = &readCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		c.mu.Unlock()
+
+		entry.value, entry.err = read(ctx)
+		close(entry.done)
+
+		if entry.err != nil {
+			c.mu.Lock()
+			if c.entries[key] == entry {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	return entry.value.(*T), nil
+}
+
+// invalidate drops every cached read. Reads already in flight finish, but
+// their results are only seen by callers that were waiting on them
+func (c *readCache) invalidate(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) > 0 {
+		tflog.Debug(ctx, "read cache invalidated", map[string]interface{}{"entries": len(c.entries)})
+	}
+	c.entries = nil
+}
+
+// providerClient returns the client of the provider providerUuid, which is
+// shared by every resource and data source of the provider
+func (p *myScribaeProvider) providerClient(providerUuid uuid.UUID) *provider.Provider {
+	p.reads.mu.Lock()
+	defer p.reads.mu.Unlock()
+
+	if p.reads.providers == nil {
+		p.reads.providers = map[uuid.UUID]*provider.Provider{}
+	}
+
+	client, ok := p.reads.providers[providerUuid]
+	if !ok {
+		client = &provider.Provider{
+			Uuid:   providerUuid,
+			Client: p.Client,
+		}
+		// ID sets the alt id on first use, so it is set here under the lock and
+		// the shared client is only read from then on
+		client.ID()
+		p.reads.providers[providerUuid] = client
+	}
+
+	return client
+}
+
+// readProvider reads the profile of prov through the read cache
+func (p *myScribaeProvider) readProvider(ctx context.Context, prov *provider.Provider) (*gql.ProviderProfile, error) {
+	return cachedRead(ctx, &p.reads, fmt.Sprintf("provider/%s", prov.ID()), prov.Read)
+}
+
+// readScriptGroup reads the profile of scriptGroup from the catalog of its
+// provider, or through the read cache when it is not in the catalog
+func (p *myScribaeProvider) readScriptGroup(ctx context.Context, scriptGroup *provider.ScriptGroup) (*gql.ScriptGroupProfile, error) {
+	catalog, ok, err := p.fetchCatalog(ctx, scriptGroup.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if profile, ok := catalog.scriptGroups[scriptGroup.AltID.String()]; ok {
+			scriptGroup.Uuid = &profile.Uuid
+			return profile, nil
+		}
+	}
+
+	profile, err := cachedRead(
+		ctx,
+		&p.reads,
+		fmt.Sprintf("script_group/%s/%s", scriptGroup.Provider.ID(), scriptGroup.AltID),
+		scriptGroup.Read,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptGroup.Uuid = &profile.Uuid
+	return profile, nil
+}
+
+// readScript reads the profile of script from the catalog of its provider, or
+// through the read cache when it is not in the catalog
+func (p *myScribaeProvider) readScript(ctx context.Context, script *provider.Script) (*gql.ScriptProfile, error) {
+	catalog, ok, err := p.fetchCatalog(ctx, script.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if profile, ok := catalog.scripts[fetchedScriptKey(script.ScriptGroupID.String(), script.AltID.String())]; ok {
+			script.Uuid = &profile.Uuid
+			return profile, nil
+		}
+	}
+
+	profile, err := cachedRead(
+		ctx,
+		&p.reads,
+		fmt.Sprintf("script/%s/%s/%s", script.Provider.ID(), script.ScriptGroupID, script.AltID),
+		script.Read,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	script.Uuid = &profile.Uuid
+	return profile, nil
+}
+
+// mutated is called after every mutation so later reads see its result
+func (p *myScribaeProvider) mutated(ctx context.Context) {
+	p.reads.invalidate(ctx)
+}
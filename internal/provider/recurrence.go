@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type recurrenceEnumQuery struct {
+	Type *struct {
+		EnumValues []struct {
+			Name string `graphql:"name"`
+		} `graphql:"enumValues"`
+	} `graphql:"__type(name: \"Recurrence\")"`
+}
+
+// Recurrences returns the recurrences accepted by the api. The result of the
+// query is cached for the lifetime of the provider, a failure included, so an
+// api that cannot answer it is only asked once rather than for every
+// recurrence in a plan. When it fails, no recurrences are returned, so
+// recurrences are only checked for their shape and membership is left to the
+// api rather than checked against a stale list
+func (p *myScribaeProvider) Recurrences(ctx context.Context) []string {
+	p.recurrencesMu.Lock()
+	defer p.recurrencesMu.Unlock()
+
+	if p.recurrences != nil || p.recurrencesFailed {
+		return p.recurrences
+	}
+
+	var query recurrenceEnumQuery
+	err := p.Client.Query(ctx, &query, nil)
+	if err != nil || query.Type == nil || len(query.Type.EnumValues) == 0 {
+		tflog.Warn(ctx, "failed to query recurrences from the api, leaving their validation to the api", map[string]interface{}{
+			"error": err,
+		})
+		// a cancelled plan says nothing about the api, so the next one
+		// asks again
+		if ctx.Err() == nil {
+			p.recurrencesFailed = true
+		}
+		return nil
+	}
+
+	recurrences := make([]string, 0, len(query.Type.EnumValues))
+	for _, v := range query.Type.EnumValues {
+		recurrences = append(recurrences, strings.ToLower(v.Name))
+	}
+	p.recurrences = recurrences
+
+	return p.recurrences
+}
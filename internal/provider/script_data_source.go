@@ -6,9 +6,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/myscribae/myscribae-sdk-go/provider"
 	"github.com/myscribae/myscribae-sdk-go/utilities"
+	"github.com/myscribae/myscribae-terraform-provider/customtypes"
 )
 
 var _ datasource.DataSource = (*scriptDataSource)(nil)
@@ -51,8 +53,9 @@ func (e *scriptDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				Required:    false,
 			},
 			"provider_id": schema.StringAttribute{
-				Description: "The provider id of the script",
-				Required:    true,
+				Description: "The provider id of the script (defaults to the default_provider_id of the provider)",
+				Optional:    true,
+				Computed:    true,
 			},
 			"script_group_id": schema.StringAttribute{
 				Description: "The script group id",
@@ -82,14 +85,34 @@ func (e *scriptDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				Description: "The sla in seconds of the script",
 				Computed:    true,
 			},
+			"sla": schema.StringAttribute{
+				Description: "The sla of the script as a duration",
+				CustomType:  customtypes.DurationType{},
+				Computed:    true,
+			},
 			"token_lifetime_sec": schema.Int64Attribute{
 				Description: "The token lifetime in seconds of the script",
 				Computed:    true,
 			},
+			"token_lifetime": schema.StringAttribute{
+				Description: "The token lifetime of the script as a duration",
+				CustomType:  customtypes.DurationType{},
+				Computed:    true,
+			},
 			"public": schema.BoolAttribute{
 				Description: "Is the script public",
 				Computed:    true,
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the script",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "All tags of the script. The same as tags, as data sources do not merge default_tags",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -99,10 +122,7 @@ func (e *scriptDataSource) MakeClient(ctx context.Context, providerId string, sc
 	if err != nil {
 		return err
 	}
-	e.myscribaeProvider = &provider.Provider{
-		Uuid:   providerUuid,
-		Client: e.terraformProvider.Client,
-	}
+	e.myscribaeProvider = e.terraformProvider.providerClient(providerUuid)
 
 	scriptGroupAltID, err := utilities.NewAltUuid(scriptGroupId)
 	if err != nil {
@@ -121,21 +141,34 @@ func (e *scriptDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	if err := e.MakeClient(ctx, data.ProviderID.ValueString(), data.ScriptGroupID.ValueString(), data.AltID.ValueString()); err != nil {
+	providerId, diags := e.terraformProvider.providerId(data.ProviderID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := e.MakeClient(ctx, providerId.ValueString(), data.ScriptGroupID.ValueString(), data.AltID.ValueString()); err != nil {
 		resp.Diagnostics.AddError("error making client", err.Error())
 		return
 	}
 
-	profile, err := e.script.Read(ctx)
+	profile, err := e.terraformProvider.readScript(ctx, e.script)
 	if err != nil {
 		resp.Diagnostics.AddError("error reading script", err.Error())
 		return
 	}
 
-	diags := resp.State.Set(ctx, &scriptResourceData{
+	remoteTags, err := e.terraformProvider.readScriptTags(ctx, e.script)
+	tags, diags := dataSourceTags(ctx, remoteTags, err)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &scriptResourceData{
 		Id:               basetypes.NewStringValue(profile.Uuid.String()),
 		Uuid:             basetypes.NewStringValue(profile.Uuid.String()),
-		ProviderID:       data.ProviderID,
+		ProviderID:       providerId,
 		ScriptGroupID:    data.ScriptGroupID,
 		AltID:            basetypes.NewStringValue(profile.AltID),
 		Name:             basetypes.NewStringValue(profile.Name),
@@ -143,8 +176,12 @@ func (e *scriptDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		Recurrence:       basetypes.NewStringValue(profile.Recurrence),
 		PriceInCents:     basetypes.NewInt64Value(int64(profile.PriceInCents)),
 		SlaSec:           basetypes.NewInt64Value(int64(profile.SlaSec)),
+		Sla:              customtypes.NewDurationSeconds(int64(profile.SlaSec)),
 		TokenLifetimeSec: basetypes.NewInt64Value(int64(profile.TokenLifetimeSec)),
+		TokenLifetime:    customtypes.NewDurationSeconds(int64(profile.TokenLifetimeSec)),
 		Public:           basetypes.NewBoolValue(profile.Public),
+		Tags:             tags,
+		TagsAll:          tags,
 	})
 	resp.Diagnostics.Append(diags...)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/myscribae/myscribae-sdk-go/provider"
 )
@@ -49,8 +50,9 @@ func (e *scriptGroupDataSource) Schema(ctx context.Context, req datasource.Schem
 				Computed:    true,
 			},
 			"provider_id": schema.StringAttribute{
-				Description: "The provider id of the script group",
-				Required:    true,
+				Description: "The provider id of the script group (defaults to the default_provider_id of the provider)",
+				Optional:    true,
+				Computed:    true,
 			},
 			"uuid": schema.StringAttribute{
 				Description: "The uuid of the script group",
@@ -72,6 +74,16 @@ func (e *scriptGroupDataSource) Schema(ctx context.Context, req datasource.Schem
 				Description: "The public status of the script group",
 				Computed:    true,
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the script group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "All tags of the script group. The same as tags, as data sources do not merge default_tags",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -82,10 +94,7 @@ func (e *scriptGroupDataSource) MakeClient(ctx context.Context, providerId strin
 		return err
 	}
 
-	e.myscribaeProvider = &provider.Provider{
-		Uuid:   providerUuid,
-		Client: e.terraformProvider.Client,
-	}
+	e.myscribaeProvider = e.terraformProvider.providerClient(providerUuid)
 	e.scriptGroup, err = e.myscribaeProvider.ScriptGroup(altId)
 	if err != nil {
 		return err
@@ -100,20 +109,40 @@ func (e *scriptGroupDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	profile, err := e.scriptGroup.Read(ctx)
+	providerId, diags := e.terraformProvider.providerId(data.ProviderId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := e.MakeClient(ctx, providerId.ValueString(), data.AltID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("error making client", err.Error())
+		return
+	}
+
+	profile, err := e.terraformProvider.readScriptGroup(ctx, e.scriptGroup)
 	if err != nil {
 		resp.Diagnostics.AddError("error reading script group", err.Error())
 		return
 	}
 
-	diags := resp.State.Set(ctx, &scriptGroupResourceData{
-		ProviderId:  data.ProviderId,
+	remoteTags, err := e.terraformProvider.readScriptGroupTags(ctx, e.scriptGroup)
+	tags, diags := dataSourceTags(ctx, remoteTags, err)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &scriptGroupResourceData{
+		ProviderId:  providerId,
 		Id:          basetypes.NewStringValue(profile.Uuid.String()),
 		Uuid:        basetypes.NewStringValue(profile.Uuid.String()),
 		AltID:       basetypes.NewStringValue(profile.AltID),
 		Name:        basetypes.NewStringValue(profile.Name),
 		Description: basetypes.NewStringValue(profile.Description),
 		Public:      basetypes.NewBoolValue(profile.Public),
+		Tags:        tags,
+		TagsAll:     tags,
 	})
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -5,24 +5,33 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/myscribae/myscribae-sdk-go/gql"
 	"github.com/myscribae/myscribae-sdk-go/provider"
 	"github.com/myscribae/myscribae-terraform-provider/validators"
 )
 
 var _ resource.Resource = (*scriptGroupResource)(nil)
 var _ resource.ResourceWithConfigure = (*scriptGroupResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*scriptGroupResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*scriptGroupResource)(nil)
+var _ resource.ResourceWithImportState = (*scriptGroupResource)(nil)
 
 type scriptGroupResource struct {
-	terraformProvider *myScribaeProvider
-	myscribaeProvider *provider.Provider
-	scriptGroup       *provider.ScriptGroup
+	// validationProvider is the provider the resource was created by, which
+	// validation reads before terraformProvider is configured
+	validationProvider *myScribaeProvider
+	terraformProvider  *myScribaeProvider
+	myscribaeProvider  *provider.Provider
+	scriptGroup        *provider.ScriptGroup
 }
 
 type scriptGroupResourceData struct {
@@ -33,6 +42,8 @@ type scriptGroupResourceData struct {
 	Name        types.String `tfsdk:"name"`
 	Description types.String `tfsdk:"description"`
 	Public      types.Bool   `tfsdk:"public"`
+	Tags        types.Map    `tfsdk:"tags"`
+	TagsAll     types.Map    `tfsdk:"tags_all"`
 }
 
 func (e *scriptGroupResource) MakeClient(ctx context.Context, providerId string, altId string) error {
@@ -41,10 +52,7 @@ func (e *scriptGroupResource) MakeClient(ctx context.Context, providerId string,
 		return err
 	}
 
-	e.myscribaeProvider = &provider.Provider{
-		Uuid:   providerUuid,
-		Client: e.terraformProvider.Client,
-	}
+	e.myscribaeProvider = e.terraformProvider.providerClient(providerUuid)
 	e.scriptGroup, err = e.myscribaeProvider.ScriptGroup(altId)
 	if err != nil {
 		return err
@@ -53,8 +61,34 @@ func (e *scriptGroupResource) MakeClient(ctx context.Context, providerId string,
 	return nil
 }
 
-func newScriptGroupResource() resource.Resource {
-	return &scriptGroupResource{}
+// lookupId returns the uuid of the script group, falling back to its alt id,
+// so the group can still be found after its alt id has changed
+func (d *scriptGroupResourceData) lookupId() string {
+	if d.Uuid.ValueString() != "" {
+		return d.Uuid.ValueString()
+	}
+
+	return d.AltID.ValueString()
+}
+
+// editScriptGroup sends the edit mutation of ScriptGroup.Update itself, as
+// the sdk panics instead of returning its errors
+func editScriptGroup(ctx context.Context, scriptGroup *provider.ScriptGroup, input provider.UpdateScriptGroupInput) error {
+	changes, err := input.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var mutation gql.EditScriptGroup
+	return scriptGroup.Provider.Client.Mutate(ctx, &mutation, map[string]interface{}{
+		"provider_id": scriptGroup.Provider.ID(),
+		"id":          scriptGroup.AltID,
+		"changes":     string(changes),
+	})
+}
+
+func newScriptGroupResource(p *myScribaeProvider) resource.Resource {
+	return &scriptGroupResource{validationProvider: p}
 }
 
 func (e *scriptGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -76,21 +110,23 @@ func (e *scriptGroupResource) Configure(ctx context.Context, req resource.Config
 
 func (e *scriptGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The id of the script group",
 				Computed:    true,
 			},
 			"provider_id": schema.StringAttribute{
-				Description: "The provider id of the script group",
-				Required:    true,
+				Description: "The provider id of the script group (defaults to the default_provider_id of the provider). Changing it replaces the script group",
+				Optional:    true,
+				Computed:    true,
 			},
 			"uuid": schema.StringAttribute{
 				Description: "The uuid of the script",
 				Computed:    true,
 			},
 			"alt_id": schema.StringAttribute{
-				Description: "The alt id of the script group",
+				Description: "The alt id of the script group. Changing it renames the script group in place",
 				Required:    true,
 				Validators: []validator.String{
 					validators.NewAltIdValidator(true),
@@ -116,11 +152,62 @@ func (e *scriptGroupResource) Schema(ctx context.Context, req resource.SchemaReq
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the script group, such as its owner team, cost center or environment",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "The tags of the script group merged over the default_tags of the myscribae provider block, as stored by the api",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
 
+func (e *scriptGroupResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		providerIdValidator{prov: e.validationProvider},
+	}
+}
+
+func (e *scriptGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planProviderId(ctx, req, resp, e.terraformProvider, true)
+	planTagsAll(ctx, req, resp, e.terraformProvider)
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var stateAltID, planAltID types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("alt_id"), &stateAltID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("alt_id"), &planAltID)...)
+	if resp.Diagnostics.HasError() || planAltID.IsUnknown() || planAltID.Equal(stateAltID) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("alt_id"),
+		"script group will be renamed",
+		fmt.Sprintf(
+			"The script group %q will be renamed to %q in place. It keeps its uuid and scripts, but anything outside terraform referring to it by %q must be updated.",
+			stateAltID.ValueString(),
+			planAltID.ValueString(),
+			stateAltID.ValueString(),
+		),
+	)
+}
+
 func (e *scriptGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	data := &scriptGroupResourceData{}
 
 	diags := req.Plan.Get(ctx, data)
@@ -144,7 +231,9 @@ func (e *scriptGroupResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	diags = resp.State.Set(ctx, &scriptGroupResourceData{
+	// the script group exists from here on, so it is saved before the tags
+	// are set
+	state := &scriptGroupResourceData{
 		Id:          basetypes.NewStringValue(resultUuid.String()),
 		Uuid:        basetypes.NewStringValue(resultUuid.String()),
 		ProviderId:  data.ProviderId,
@@ -152,7 +241,34 @@ func (e *scriptGroupResource) Create(ctx context.Context, req resource.CreateReq
 		Name:        data.Name,
 		Description: data.Description,
 		Public:      data.Public,
-	})
+		Tags:        types.MapNull(types.StringType),
+		TagsAll:     types.MapNull(types.StringType),
+	}
+	if diags := resp.State.Set(ctx, state); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, data.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.IsNull() {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setScriptGroupTags(ctx, e.scriptGroup, tags); err != nil {
+			tagsNotSetWarning(&resp.Diagnostics, "script group", err)
+		}
+	}
+
+	state.Tags = data.Tags
+	state.TagsAll = tagsAll
+
+	diags = resp.State.Set(ctx, state)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
@@ -168,18 +284,25 @@ func (e *scriptGroupResource) Read(ctx context.Context, req resource.ReadRequest
 		resp.Diagnostics.Append(diags...)
 		return
 	}
-	if err := e.MakeClient(ctx, data.ProviderId.ValueString(), data.AltID.ValueString()); err != nil {
+	if err := e.MakeClient(ctx, data.ProviderId.ValueString(), data.lookupId()); err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("failed to create script group client for read: %s", err), err.Error())
 		return
 	}
 
 	// Set the data in the response
-	profile, err := e.scriptGroup.Read(ctx)
+	profile, err := e.terraformProvider.readScriptGroup(ctx, e.scriptGroup)
 	if err != nil {
 		resp.Diagnostics.AddError("failed to get script group", err.Error())
 		return
 	}
 
+	tags, tagsAll := e.terraformProvider.readResourceTags(ctx, "script group", func(ctx context.Context) (map[string]string, error) {
+		return e.terraformProvider.readScriptGroupTags(ctx, e.scriptGroup)
+	}, data.Tags, data.TagsAll, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	diags = resp.State.Set(ctx, &scriptGroupResourceData{
 		Id:          basetypes.NewStringValue(profile.Uuid.String()),
 		Uuid:        basetypes.NewStringValue(profile.Uuid.String()),
@@ -188,6 +311,8 @@ func (e *scriptGroupResource) Read(ctx context.Context, req resource.ReadRequest
 		Name:        basetypes.NewStringValue(profile.Name),
 		Description: basetypes.NewStringValue(profile.Description),
 		Public:      basetypes.NewBoolValue(profile.Public),
+		Tags:        tags,
+		TagsAll:     tagsAll,
 	})
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -195,7 +320,25 @@ func (e *scriptGroupResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 }
 
+// ImportState imports a script group by <provider_id>/<uuid or alt_id>
+func (e *scriptGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, ok := splitImportId(req.ID, "<provider_id>/<uuid or alt_id>", resp)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("provider_id"), parts[0])...)
+	if isUuid(parts[1]) {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), parts[1])...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alt_id"), parts[1])...)
+	}
+	markImported(ctx, resp)
+}
+
 func (e *scriptGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	state := scriptGroupResourceData{}
 	diags := req.State.Get(ctx, &state)
 	if diags.HasError() {
@@ -210,24 +353,51 @@ func (e *scriptGroupResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	if err := e.MakeClient(ctx, data.ProviderId.ValueString(), data.AltID.ValueString()); err != nil {
+	// resolve the group from state, the planned alt id does not exist yet when renaming
+	if err := e.MakeClient(ctx, state.ProviderId.ValueString(), state.lookupId()); err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("failed to create script group client for update: %s", err), err.Error())
 		return
 	}
 
-	_, err := e.scriptGroup.Update(ctx, provider.UpdateScriptGroupInput{
+	var altId *string
+	if !data.AltID.Equal(state.AltID) {
+		altId = data.AltID.ValueStringPointer()
+	}
+
+	err := editScriptGroup(ctx, e.scriptGroup, provider.UpdateScriptGroupInput{
+		AltID:       altId,
 		Name:        data.Name.ValueStringPointer(),
 		Description: data.Description.ValueStringPointer(),
 		Public:      data.Public.ValueBoolPointer(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("failed to update script group: %s", err.Error())
+		resp.Diagnostics.AddError("failed to update script group", err.Error())
 		return
 	}
 
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, data.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.Equal(state.TagsAll) {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setScriptGroupTags(ctx, e.scriptGroup, tags); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("tags"), "failed to set script group tags", err.Error())
+			return
+		}
+	}
+
+	state.AltID = data.AltID
 	state.Name = data.Name
 	state.Description = data.Description
 	state.Public = data.Public
+	state.Tags = data.Tags
+	state.TagsAll = tagsAll
 
 	diags = resp.State.Set(ctx, &state)
 	if diags.HasError() {
@@ -237,6 +407,8 @@ func (e *scriptGroupResource) Update(ctx context.Context, req resource.UpdateReq
 }
 
 func (e *scriptGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	data := scriptGroupResourceData{}
 	diags := req.State.Get(ctx, &data)
 	if diags.HasError() {
@@ -244,17 +416,17 @@ func (e *scriptGroupResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	if err := e.MakeClient(ctx, data.ProviderId.ValueString(), data.AltID.ValueString()); err != nil {
+	if err := e.MakeClient(ctx, data.ProviderId.ValueString(), data.lookupId()); err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("failed to create script group client for delete: %s", err), err.Error())
 		return
 	}
 
 	var public = false
-	_, err := e.scriptGroup.Update(ctx, provider.UpdateScriptGroupInput{
+	err := editScriptGroup(ctx, e.scriptGroup, provider.UpdateScriptGroupInput{
 		Public: &public,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("failed to delete script group: %s", err.Error())
+		resp.Diagnostics.AddError("failed to delete script group", err.Error())
 		return
 	}
 }
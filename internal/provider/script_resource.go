@@ -2,10 +2,15 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,37 +21,58 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/myscribae/myscribae-sdk-go/gql"
 	"github.com/myscribae/myscribae-sdk-go/provider"
 	"github.com/myscribae/myscribae-sdk-go/utilities"
+	"github.com/myscribae/myscribae-terraform-provider/customtypes"
 	"github.com/myscribae/myscribae-terraform-provider/validators"
 )
 
 var _ resource.Resource = (*scriptResource)(nil)
 var _ resource.ResourceWithConfigure = (*scriptResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*scriptResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*scriptResource)(nil)
+var _ resource.ResourceWithImportState = (*scriptResource)(nil)
 
 type scriptResource struct {
-	terraformProvider *myScribaeProvider
-	myscribaeProvider *provider.Provider
-	script            *provider.Script
+	// validationProvider is the provider the resource was created by, which
+	// validation reads before terraformProvider is configured
+	validationProvider *myScribaeProvider
+	terraformProvider  *myScribaeProvider
+	myscribaeProvider  *provider.Provider
+	script             *provider.Script
 }
 
 type scriptResourceData struct {
-	ProviderID       types.String `tfsdk:"provider_id"`
-	ScriptGroupID    types.String `tfsdk:"script_group_id"`
-	Id               types.String `tfsdk:"id"`
-	Uuid             types.String `tfsdk:"uuid"`
-	AltID            types.String `tfsdk:"alt_id"`
-	Name             types.String `tfsdk:"name"`
-	Description      types.String `tfsdk:"description"`
-	Recurrence       types.String `tfsdk:"recurrence"`
-	PriceInCents     types.Int64  `tfsdk:"price_in_cents"`
-	SlaSec           types.Int64  `tfsdk:"sla_sec"`
-	TokenLifetimeSec types.Int64  `tfsdk:"token_lifetime_sec"`
-	Public           types.Bool   `tfsdk:"public"`
+	ProviderID       types.String         `tfsdk:"provider_id"`
+	ScriptGroupID    types.String         `tfsdk:"script_group_id"`
+	Id               types.String         `tfsdk:"id"`
+	Uuid             types.String         `tfsdk:"uuid"`
+	AltID            types.String         `tfsdk:"alt_id"`
+	Name             types.String         `tfsdk:"name"`
+	Description      types.String         `tfsdk:"description"`
+	Recurrence       types.String         `tfsdk:"recurrence"`
+	PriceInCents     types.Int64          `tfsdk:"price_in_cents"`
+	SlaSec           types.Int64          `tfsdk:"sla_sec"`
+	Sla              customtypes.Duration `tfsdk:"sla"`
+	TokenLifetimeSec types.Int64          `tfsdk:"token_lifetime_sec"`
+	TokenLifetime    customtypes.Duration `tfsdk:"token_lifetime"`
+	Public           types.Bool           `tfsdk:"public"`
+	Tags             types.Map            `tfsdk:"tags"`
+	TagsAll          types.Map            `tfsdk:"tags_all"`
 }
 
-func newScriptResource() resource.Resource {
-	return &scriptResource{}
+// lookupId returns the uuid of the script, falling back to its alt id
+func (d *scriptResourceData) lookupId() string {
+	if d.Uuid.ValueString() != "" {
+		return d.Uuid.ValueString()
+	}
+
+	return d.AltID.ValueString()
+}
+
+func newScriptResource(p *myScribaeProvider) resource.Resource {
+	return &scriptResource{validationProvider: p}
 }
 
 func (e *scriptResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -78,41 +104,47 @@ func (e *scriptResource) MakeClient(ctx context.Context, providerId string, scri
 		return err
 	}
 
-	e.myscribaeProvider = &provider.Provider{
-		Uuid:   providerUuid,
-		Client: e.terraformProvider.Client,
-	}
+	e.myscribaeProvider = e.terraformProvider.providerClient(providerUuid)
 	e.script, err = e.myscribaeProvider.Script(scriptGroupAltID, altId)
 	return err
 }
 
 func (e *scriptResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "The id of the script",
 				Computed:    true,
 			},
 			"provider_id": schema.StringAttribute{
-				Description: "The provider id of the script",
-				Required:    true,
+				Description: "The provider id of the script (defaults to the default_provider_id of the provider). Changing it replaces the script",
+				Optional:    true,
+				Computed:    true,
 				Validators: []validator.String{
 					validators.NewUuidValidator(false),
 				},
 			},
 			"script_group_id": schema.StringAttribute{
-				Description: "The script group uuid",
+				Description: "The script group uuid. Changing it moves the script to that group in place, keeping its uuid and subscribers",
 				Required:    true,
 				Validators: []validator.String{
 					validators.NewUuidValidator(false),
 				},
 			},
 			"alt_id": schema.StringAttribute{
-				Description: "The alt id of the script",
+				Description: "The alt id of the script. The script update of the sdk takes no alt id, unlike the script group update, so changing it replaces the script",
 				Required:    true,
 				Validators: []validator.String{
 					validators.NewAltIdValidator(true),
 				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						altIdChanged,
+						"The script update of the sdk takes no alt id, so changing alt_id replaces the script.",
+						"The script update of the sdk takes no alt id, so changing `alt_id` replaces the script.",
+					),
+				},
 			},
 			"uuid": schema.StringAttribute{
 				Description: "The uuid of the script",
@@ -133,7 +165,7 @@ func (e *scriptResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"recurrence": schema.StringAttribute{
-				Description: "The recurrence of the script",
+				Description: "The recurrence of the script (one of the recurrences supported by the api, e.g. monthly)",
 				Required:    true,
 				Validators: []validator.String{
 					validators.NewRecurrenceValidator(),
@@ -143,24 +175,44 @@ func (e *scriptResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"price_in_cents": schema.Int64Attribute{
-				Description: "The price in cents of the script (minimum 1)",
+				Description: "The price in cents of the script (between 1 and 4294967295)",
 				Required:    true,
 				Validators: []validator.Int64{
-					int64validator.AtLeast(1),
+					int64validator.Between(1, math.MaxUint32),
 				},
 			},
 			"sla_sec": schema.Int64Attribute{
-				Description: "The SLA in seconds of the script (minimum 2400)",
-				Required:    true,
+				Description: "The SLA in seconds of the script (between 2400 and 4294967295, conflicts with sla, must not be shorter than the token lifetime)",
+				Optional:    true,
+				Computed:    true,
 				Validators: []validator.Int64{
-					int64validator.AtLeast(2400),
+					int64validator.Between(2400, math.MaxUint32),
+				},
+			},
+			"sla": schema.StringAttribute{
+				Description: "The SLA of the script as a duration, e.g. 1h (at least 40m, conflicts with sla_sec, must not be shorter than the token lifetime)",
+				CustomType:  customtypes.DurationType{},
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.NewDurationValidator(2400*time.Second, math.MaxUint32*time.Second),
 				},
 			},
 			"token_lifetime_sec": schema.Int64Attribute{
-				Description: "The token lifetime in seconds of the script (minimum 600)",
-				Required:    true,
+				Description: "The token lifetime in seconds of the script (between 600 and 4294967295, conflicts with token_lifetime, must not exceed the sla)",
+				Optional:    true,
+				Computed:    true,
 				Validators: []validator.Int64{
-					int64validator.AtLeast(600),
+					int64validator.Between(600, math.MaxUint32),
+				},
+			},
+			"token_lifetime": schema.StringAttribute{
+				Description: "The token lifetime of the script as a duration, e.g. 30m (at least 10m, conflicts with token_lifetime_sec, must not exceed the sla)",
+				CustomType:  customtypes.DurationType{},
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.NewDurationValidator(600*time.Second, math.MaxUint32*time.Second),
 				},
 			},
 			"public": schema.BoolAttribute{
@@ -169,78 +221,189 @@ func (e *scriptResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the script, such as its owner team, cost center or environment",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "The tags of the script merged over the default_tags of the myscribae provider block, as stored by the api",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
 
-func (e *scriptResource) Plan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
-	data := scriptResourceData{}
-	diags := req.State.Get(ctx, &data)
+func (e *scriptResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("sla"),
+			path.MatchRoot("sla_sec"),
+		),
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("token_lifetime"),
+			path.MatchRoot("token_lifetime_sec"),
+		),
+		scriptTimingValidator{},
+		providerIdValidator{prov: e.validationProvider},
+	}
+}
+
+func (e *scriptResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planProviderId(ctx, req, resp, e.terraformProvider, true)
+	planTagsAll(ctx, req, resp, e.terraformProvider)
+	planMove(ctx, req, resp)
+	planDuration(ctx, req, resp, "sla", "sla_sec")
+	planDuration(ctx, req, resp, "token_lifetime", "token_lifetime_sec")
+	if resp.Diagnostics.HasError() || e.terraformProvider == nil {
+		return
+	}
+
+	var recurrence types.String
+	diags := req.Plan.GetAttribute(ctx, path.Root("recurrence"), &recurrence)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	recurrence := data.Recurrence.ValueStringPointer()
-	// Check if recurrence
-	if recurrence != nil && *recurrence != "" {
-		diags = resp.Plan.SetAttribute(ctx, path.Root("recurrence"), data.Recurrence)
+	if !req.State.Raw.IsNull() {
+		var stateRecurrence types.String
+		diags = req.State.GetAttribute(ctx, path.Root("recurrence"), &stateRecurrence)
 		if diags.HasError() {
 			resp.Diagnostics.Append(diags...)
 			return
 		}
+		if stateRecurrence.Equal(recurrence) {
+			return
+		}
 	}
 
+	// the recurrences are only known once the provider is configured, so
+	// membership is checked here rather than in the schema validator
+	validatorResp := &validator.StringResponse{}
+	validators.NewRecurrenceValidator(e.terraformProvider.Recurrences(ctx)...).ValidateString(
+		ctx,
+		validator.StringRequest{
+			Path:        path.Root("recurrence"),
+			ConfigValue: recurrence,
+		},
+		validatorResp,
+	)
+	for _, d := range validatorResp.Diagnostics {
+		resp.Diagnostics.AddAttributeError(path.Root("recurrence"), d.Summary(), d.Detail())
+	}
 }
 
-func (e *scriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	data := scriptResourceData{}
-	diags := req.Plan.Get(ctx, &data)
-	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
+// altIdChanged requires replacement when the alt id of an existing script
+// changes, warning that a new script with a new uuid will be created. The
+// UpdateScriptInput of the sdk has no alt id, so a script cannot be renamed in
+// place the way a script group can
+func altIdChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	if req.PlanValue.IsUnknown() || req.PlanValue.Equal(req.StateValue) {
 		return
 	}
 
-	if err := e.MakeClient(ctx, data.ProviderID.ValueString(), data.ScriptGroupID.ValueString(), data.AltID.ValueString()); err != nil {
-		resp.Diagnostics.AddError(
-			"failed to create client",
-			err.Error(),
-		)
+	resp.RequiresReplace = true
+	resp.Diagnostics.AddAttributeWarning(
+		req.Path,
+		"script will be replaced",
+		fmt.Sprintf(
+			"Scripts cannot be renamed in place, the script update of the sdk takes no alt id. Changing alt_id from %q to %q unpublishes the existing script and creates a new one with a new uuid. Existing subscribers stay on the old script.",
+			req.StateValue.ValueString(),
+			req.PlanValue.ValueString(),
+		),
+	)
+}
+
+// planMove warns when the script will be moved to another script group
+func planMove(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
 		return
 	}
 
-	recurrence, err := utilities.NewRecurrence(data.Recurrence.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to parse recurrence",
-			err.Error(),
-		)
+	var stateGroupId, planGroupId types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("script_group_id"), &stateGroupId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("script_group_id"), &planGroupId)...)
+	if resp.Diagnostics.HasError() || planGroupId.IsUnknown() || planGroupId.Equal(stateGroupId) {
 		return
 	}
 
-	priceInCents := uint64(data.PriceInCents.ValueInt64())
-	if priceInCents > math.MaxUint32 {
-		resp.Diagnostics.AddError(
-			"price_in_cents is too large",
-			"price_in_cents must be less than 4294967296",
-		)
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("script_group_id"),
+		"script will be moved",
+		fmt.Sprintf(
+			"The script will be moved from script group %s to %s in place. It keeps its uuid and subscribers.",
+			stateGroupId.ValueString(),
+			planGroupId.ValueString(),
+		),
+	)
+}
+
+// planDuration fills in whichever of a duration and its seconds form was not
+// configured, so the plan shows both values before apply
+func planDuration(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, durationAttr string, secAttr string) {
+	var (
+		duration customtypes.Duration
+		sec      types.Int64
+	)
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(durationAttr), &duration)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(secAttr), &sec)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	slaSec := data.SlaSec.ValueInt64()
-	if slaSec > math.MaxUint32 {
-		resp.Diagnostics.AddError(
-			"sla_sec is too large",
-			"sla_sec must be less than 4294967296",
-		)
+	switch {
+	case duration.IsUnknown():
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(secAttr), types.Int64Unknown())...)
+	case !duration.IsNull():
+		seconds, diags := duration.ValueSeconds()
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(secAttr), types.Int64Value(seconds))...)
+	case sec.IsUnknown():
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(durationAttr), customtypes.NewDurationUnknown())...)
+	case !sec.IsNull():
+		planned := customtypes.NewDurationSeconds(sec.ValueInt64())
+		if !req.State.Raw.IsNull() {
+			var (
+				stateDuration customtypes.Duration
+				stateSec      types.Int64
+			)
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(durationAttr), &stateDuration)...)
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(secAttr), &stateSec)...)
+			if !stateDuration.IsNull() && stateSec.Equal(sec) {
+				planned = stateDuration
+			}
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(durationAttr), planned)...)
+	}
+}
+
+func (e *scriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
+	data := scriptResourceData{}
+	diags := req.Plan.Get(ctx, &data)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	tokenLifetimeSec := data.TokenLifetimeSec.ValueInt64()
-	if tokenLifetimeSec > math.MaxUint32 {
+	if err := e.MakeClient(ctx, data.ProviderID.ValueString(), data.ScriptGroupID.ValueString(), data.AltID.ValueString()); err != nil {
 		resp.Diagnostics.AddError(
-			"token_lifetime_sec is too large",
-			"token_lifetime_sec must be less than 4294967296",
+			"failed to create client",
+			err.Error(),
 		)
 		return
 	}
@@ -249,10 +412,10 @@ func (e *scriptResource) Create(ctx context.Context, req resource.CreateRequest,
 		AltID:            data.AltID.ValueString(),
 		Name:             data.Name.ValueString(),
 		Description:      data.Description.ValueString(),
-		Recurrence:       *recurrence,
-		PriceInCents:     utilities.NewCentValue(uint(priceInCents)),
-		SlaSec:           utilities.NewUInt(uint(slaSec)),
-		TokenLifetimeSec: utilities.NewUInt(uint(tokenLifetimeSec)),
+		Recurrence:       utilities.Recurrence(data.Recurrence.ValueString()),
+		PriceInCents:     utilities.NewCentValue(uint(data.PriceInCents.ValueInt64())),
+		SlaSec:           utilities.NewUInt(uint(data.SlaSec.ValueInt64())),
+		TokenLifetimeSec: utilities.NewUInt(uint(data.TokenLifetimeSec.ValueInt64())),
 		Public:           data.Public.ValueBool(),
 	})
 	if err != nil {
@@ -263,7 +426,8 @@ func (e *scriptResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	diags = resp.State.Set(ctx, &scriptResourceData{
+	// the script exists from here on, so it is saved before the tags are set
+	state := &scriptResourceData{
 		Id:               basetypes.NewStringValue(resultUuid.String()),
 		Uuid:             basetypes.NewStringValue(resultUuid.String()),
 		ProviderID:       data.ProviderID,
@@ -274,9 +438,38 @@ func (e *scriptResource) Create(ctx context.Context, req resource.CreateRequest,
 		Recurrence:       data.Recurrence,
 		PriceInCents:     data.PriceInCents,
 		SlaSec:           data.SlaSec,
+		Sla:              data.Sla,
 		TokenLifetimeSec: data.TokenLifetimeSec,
+		TokenLifetime:    data.TokenLifetime,
 		Public:           data.Public,
-	})
+		Tags:             types.MapNull(types.StringType),
+		TagsAll:          types.MapNull(types.StringType),
+	}
+	if diags := resp.State.Set(ctx, state); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, data.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.IsNull() {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setScriptTags(ctx, e.script, tags); err != nil {
+			tagsNotSetWarning(&resp.Diagnostics, "script", err)
+		}
+	}
+
+	state.Tags = data.Tags
+	state.TagsAll = tagsAll
+
+	diags = resp.State.Set(ctx, state)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
@@ -291,7 +484,7 @@ func (e *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	if err := e.MakeClient(ctx, stateData.ProviderID.ValueString(), stateData.ScriptGroupID.ValueString(), stateData.AltID.ValueString()); err != nil {
+	if err := e.MakeClient(ctx, stateData.ProviderID.ValueString(), stateData.ScriptGroupID.ValueString(), stateData.lookupId()); err != nil {
 		resp.Diagnostics.AddError(
 			"failed to create client",
 			err.Error(),
@@ -299,7 +492,7 @@ func (e *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	profile, err := e.script.Read(ctx)
+	profile, err := e.terraformProvider.readScript(ctx, e.script)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"failed to get script profile",
@@ -308,6 +501,13 @@ func (e *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	tags, tagsAll := e.terraformProvider.readResourceTags(ctx, "script", func(ctx context.Context) (map[string]string, error) {
+		return e.terraformProvider.readScriptTags(ctx, e.script)
+	}, stateData.Tags, stateData.TagsAll, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	diags = resp.State.Set(ctx, &scriptResourceData{
 		Id:               basetypes.NewStringValue(profile.Uuid.String()),
 		Uuid:             basetypes.NewStringValue(profile.Uuid.String()),
@@ -319,8 +519,12 @@ func (e *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 		Recurrence:       basetypes.NewStringValue(profile.Recurrence),
 		PriceInCents:     basetypes.NewInt64Value(int64(profile.PriceInCents)),
 		SlaSec:           basetypes.NewInt64Value(int64(profile.SlaSec)),
+		Sla:              customtypes.NewDurationSeconds(int64(profile.SlaSec)),
 		TokenLifetimeSec: basetypes.NewInt64Value(int64(profile.TokenLifetimeSec)),
+		TokenLifetime:    customtypes.NewDurationSeconds(int64(profile.TokenLifetimeSec)),
 		Public:           basetypes.NewBoolValue(profile.Public),
+		Tags:             tags,
+		TagsAll:          tagsAll,
 	})
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -328,7 +532,27 @@ func (e *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 }
 
+// ImportState imports a script by
+// <provider_id>/<script_group_id>/<uuid or alt_id>
+func (e *scriptResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, ok := splitImportId(req.ID, "<provider_id>/<script_group_id>/<uuid or alt_id>", resp)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("provider_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("script_group_id"), parts[1])...)
+	if isUuid(parts[2]) {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), parts[2])...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alt_id"), parts[2])...)
+	}
+	markImported(ctx, resp)
+}
+
 func (e *scriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	stateData := scriptResourceData{}
 	diags := req.State.Get(ctx, &stateData)
 	if diags.HasError() {
@@ -343,7 +567,8 @@ func (e *scriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	if err := e.MakeClient(ctx, planData.ProviderID.ValueString(), planData.ScriptGroupID.ValueString(), planData.AltID.ValueString()); err != nil {
+	// resolve the script in the group it is in now, a move happens after the update
+	if err := e.MakeClient(ctx, stateData.ProviderID.ValueString(), stateData.ScriptGroupID.ValueString(), stateData.lookupId()); err != nil {
 		resp.Diagnostics.AddError(
 			"failed to create client",
 			err.Error(),
@@ -351,36 +576,10 @@ func (e *scriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	priceInCents := uint64(planData.PriceInCents.ValueInt64())
-	if priceInCents > math.MaxUint32 {
-		resp.Diagnostics.AddError(
-			"price_in_cents is too large",
-			"price_in_cents must be less than 4294967296",
-		)
-		return
-	}
-
-	slaSec := planData.SlaSec.ValueInt64()
-	if slaSec > math.MaxUint32 {
-		resp.Diagnostics.AddError(
-			"sla_sec is too large",
-			"sla_sec must be less than 4294967296",
-		)
-		return
-	}
-
-	tokenLifetimeSec := planData.TokenLifetimeSec.ValueInt64()
-	if tokenLifetimeSec > math.MaxUint32 {
-		resp.Diagnostics.AddError(
-			"token_lifetime_sec is too large",
-			"token_lifetime_sec must be less than 4294967296",
-		)
-	}
-
 	var (
-		_priceInCents     = utilities.NewCentValue(uint(priceInCents))
-		_slaSec           = utilities.NewUInt(uint(slaSec))
-		_tokenLifetimeSec = utilities.NewUInt(uint(tokenLifetimeSec))
+		_priceInCents     = utilities.NewCentValue(uint(planData.PriceInCents.ValueInt64()))
+		_slaSec           = utilities.NewUInt(uint(planData.SlaSec.ValueInt64()))
+		_tokenLifetimeSec = utilities.NewUInt(uint(planData.TokenLifetimeSec.ValueInt64()))
 	)
 
 	resultUuid, err := e.script.Update(ctx, provider.UpdateScriptInput{
@@ -399,12 +598,47 @@ func (e *scriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// tags are set before a move, while the script is still in the group it
+	// was resolved in
+	tagsAll, diags := e.terraformProvider.tagsAll(ctx, planData.Tags)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if !tagsAll.Equal(stateData.TagsAll) {
+		tags, diags := tagsMap(ctx, tagsAll)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := setScriptTags(ctx, e.script, tags); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("tags"), "failed to set script tags", err.Error())
+			return
+		}
+	}
+
+	if !planData.ScriptGroupID.Equal(stateData.ScriptGroupID) {
+		if err := moveScript(ctx, e.script, planData.ScriptGroupID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("script_group_id"),
+				"failed to move script",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	stateData.ScriptGroupID = planData.ScriptGroupID
 	stateData.Name = planData.Name
 	stateData.Description = planData.Description
 	stateData.PriceInCents = planData.PriceInCents
 	stateData.SlaSec = planData.SlaSec
+	stateData.Sla = planData.Sla
 	stateData.TokenLifetimeSec = planData.TokenLifetimeSec
+	stateData.TokenLifetime = planData.TokenLifetime
 	stateData.Public = planData.Public
+	stateData.Tags = planData.Tags
+	stateData.TagsAll = tagsAll
 	stateData.Uuid = basetypes.NewStringValue(resultUuid.String())
 	stateData.Id = basetypes.NewStringValue(resultUuid.String())
 
@@ -415,7 +649,53 @@ func (e *scriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 }
 
+// moveScript moves a script into another script group of the same provider
+// with an edit mutation that sets its group. It keeps its uuid and
+// subscribers. The sdk does not model the move, so the script is read back
+// from the new group to make sure the api made it
+func moveScript(ctx context.Context, script *provider.Script, scriptGroupId string) error {
+	scriptGroupAltId, err := utilities.NewAltUuid(scriptGroupId)
+	if err != nil {
+		return err
+	}
+
+	changes, err := json.Marshal(map[string]interface{}{
+		"script_group_id": scriptGroupId,
+	})
+	if err != nil {
+		return err
+	}
+
+	var mutation gql.EditScript
+	if err := script.Provider.Client.Mutate(ctx, &mutation, map[string]interface{}{
+		"provider_id":     script.Provider.ID(),
+		"script_group_id": script.ScriptGroupID,
+		"id":              script.AltID,
+		"changes":         string(changes),
+	}); err != nil {
+		return err
+	}
+
+	id := script.AltID.String()
+	if mutation.Provider.ScriptGroup.Script.Edit.Uuid != uuid.Nil {
+		id = mutation.Provider.ScriptGroup.Script.Edit.Uuid.String()
+	}
+
+	moved, err := script.Provider.Script(scriptGroupAltId, id)
+	if err != nil {
+		return err
+	}
+	if _, err := moved.Read(ctx); err != nil {
+		return fmt.Errorf("the api accepted the move but the script is not in script group %s: %w", scriptGroupId, err)
+	}
+
+	script.ScriptGroupID = scriptGroupAltId
+	return nil
+}
+
 func (e *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	defer e.terraformProvider.mutated(ctx)
+
 	data := scriptResourceData{}
 	diags := req.State.Get(ctx, &data)
 	if diags.HasError() {
@@ -423,7 +703,7 @@ func (e *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	if err := e.MakeClient(ctx, data.ProviderID.ValueString(), data.ScriptGroupID.ValueString(), data.AltID.ValueString()); err != nil {
+	if err := e.MakeClient(ctx, data.ProviderID.ValueString(), data.ScriptGroupID.ValueString(), data.lookupId()); err != nil {
 		resp.Diagnostics.AddError(
 			"failed to create client",
 			err.Error(),
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myscribae/myscribae-terraform-provider/customtypes"
+)
+
+// scriptTimingValidator rejects scripts whose subscriber tokens would outlive
+// the sla, which the api refuses at runtime
+type scriptTimingValidator struct{}
+
+var _ resource.ConfigValidator = (*scriptTimingValidator)(nil)
+
+func (v scriptTimingValidator) Description(ctx context.Context) string {
+	return "token lifetime must not exceed the sla"
+}
+
+func (v scriptTimingValidator) MarkdownDescription(ctx context.Context) string {
+	return "token lifetime must not exceed the sla"
+}
+
+func (v scriptTimingValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	slaSec, _, known := configSeconds(ctx, req.Config, "sla", "sla_sec", &resp.Diagnostics)
+	if !known {
+		return
+	}
+
+	tokenLifetimeSec, tokenLifetimePath, known := configSeconds(ctx, req.Config, "token_lifetime", "token_lifetime_sec", &resp.Diagnostics)
+	if !known {
+		return
+	}
+
+	if tokenLifetimeSec > slaSec {
+		resp.Diagnostics.AddAttributeError(
+			tokenLifetimePath,
+			"token lifetime exceeds sla",
+			fmt.Sprintf("the token lifetime (%ds) must not be longer than the sla (%ds)", tokenLifetimeSec, slaSec),
+		)
+	}
+}
+
+// configSeconds returns the configured seconds of a duration attribute or its
+// seconds form, along with the path it was configured at
+func configSeconds(ctx context.Context, config tfsdk.Config, durationAttr string, secAttr string, diags *diag.Diagnostics) (int64, path.Path, bool) {
+	var (
+		duration customtypes.Duration
+		sec      types.Int64
+	)
+
+	diags.Append(config.GetAttribute(ctx, path.Root(durationAttr), &duration)...)
+	diags.Append(config.GetAttribute(ctx, path.Root(secAttr), &sec)...)
+	if diags.HasError() {
+		return 0, path.Empty(), false
+	}
+
+	if !duration.IsNull() && !duration.IsUnknown() {
+		seconds, d := duration.ValueSeconds()
+		if d.HasError() {
+			// reported by the duration type
+			return 0, path.Empty(), false
+		}
+		return seconds, path.Root(durationAttr), true
+	}
+
+	if !sec.IsNull() && !sec.IsUnknown() {
+		return sec.ValueInt64(), path.Root(secAttr), true
+	}
+
+	return 0, path.Empty(), false
+}
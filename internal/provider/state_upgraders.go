@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myscribae/myscribae-terraform-provider/customtypes"
+)
+
+// Every resource schema carries a version. When a schema changes in a way
+// existing state cannot be read with, bump its version, freeze the previous
+// schema and model below and add an upgrader from it to the resource's
+// UpgradeState. Terraform chains nothing itself, so each upgrader must produce
+// the current state directly.
+
+var _ resource.ResourceWithUpgradeState = (*myscribaeProviderResource)(nil)
+var _ resource.ResourceWithUpgradeState = (*scriptGroupResource)(nil)
+var _ resource.ResourceWithUpgradeState = (*scriptResource)(nil)
+
+// stateUpgrader reads state written with priorSchema into P and stores the
+// result of upgrade as the current state
+func stateUpgrader[P any, C any](priorSchema schema.Schema, upgrade func(context.Context, P) (C, diag.Diagnostics)) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		PriorSchema: &priorSchema,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var prior P
+			resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			current, diags := upgrade(ctx, prior)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, current)...)
+		},
+	}
+}
+
+// myscribae_provider
+
+type myscribaeProviderResourceDataV0 struct {
+	Id             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	AltID          types.String `tfsdk:"alt_id"`
+	Uuid           types.String `tfsdk:"uuid"`
+	Description    types.String `tfsdk:"description"`
+	LogoUrl        types.String `tfsdk:"logo_url"`
+	BannerUrl      types.String `tfsdk:"banner_url"`
+	Url            types.String `tfsdk:"url"`
+	Color          types.String `tfsdk:"color"`
+	Public         types.Bool   `tfsdk:"public"`
+	AccountService types.Bool   `tfsdk:"account_service"`
+	SecretKey      types.String `tfsdk:"secret_key"`
+	ApiKey         types.String `tfsdk:"api_key"`
+}
+
+func myscribaeProviderResourceSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":              schema.StringAttribute{Computed: true},
+			"alt_id":          schema.StringAttribute{Optional: true},
+			"uuid":            schema.StringAttribute{Optional: true},
+			"name":            schema.StringAttribute{Required: true},
+			"description":     schema.StringAttribute{Required: true},
+			"logo_url":        schema.StringAttribute{Optional: true},
+			"banner_url":      schema.StringAttribute{Optional: true},
+			"url":             schema.StringAttribute{Optional: true},
+			"color":           schema.StringAttribute{Optional: true, Computed: true},
+			"public":          schema.BoolAttribute{Optional: true, Computed: true},
+			"account_service": schema.BoolAttribute{Optional: true, Computed: true},
+			"secret_key":      schema.StringAttribute{Computed: true, Sensitive: true},
+			"api_key":         schema.StringAttribute{Computed: true, Sensitive: true},
+		},
+	}
+}
+
+func (e *myscribaeProviderResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: stateUpgrader(myscribaeProviderResourceSchemaV0(), upgradeProviderResourceFromV0),
+	}
+}
+
+func upgradeProviderResourceFromV0(ctx context.Context, prior myscribaeProviderResourceDataV0) (myscribaeProviderResourceData, diag.Diagnostics) {
+	return myscribaeProviderResourceData{
+		Id:             prior.Id,
+		Name:           prior.Name,
+		AltID:          prior.AltID,
+		Uuid:           prior.Uuid,
+		Description:    prior.Description,
+		LogoUrl:        prior.LogoUrl,
+		BannerUrl:      prior.BannerUrl,
+		Url:            prior.Url,
+		Color:          prior.Color,
+		Public:         prior.Public,
+		AccountService: prior.AccountService,
+		AdoptExisting:  types.BoolValue(false),
+		KeyManagement:  types.StringValue(keyManagementPreserve),
+		SecretKey:      prior.SecretKey,
+		ApiKey:         prior.ApiKey,
+		Tags:           types.MapNull(types.StringType),
+		TagsAll:        types.MapNull(types.StringType),
+	}, nil
+}
+
+// myscribae_script_group
+
+type scriptGroupResourceDataV0 struct {
+	ProviderId  types.String `tfsdk:"provider_id"`
+	Id          types.String `tfsdk:"id"`
+	Uuid        types.String `tfsdk:"uuid"`
+	AltID       types.String `tfsdk:"alt_id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Public      types.Bool   `tfsdk:"public"`
+}
+
+func scriptGroupResourceSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":          schema.StringAttribute{Computed: true},
+			"provider_id": schema.StringAttribute{Required: true},
+			"uuid":        schema.StringAttribute{Computed: true},
+			"alt_id":      schema.StringAttribute{Required: true},
+			"name":        schema.StringAttribute{Required: true},
+			"description": schema.StringAttribute{Required: true},
+			"public":      schema.BoolAttribute{Optional: true, Computed: true},
+		},
+	}
+}
+
+func (e *scriptGroupResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: stateUpgrader(scriptGroupResourceSchemaV0(), upgradeScriptGroupResourceFromV0),
+	}
+}
+
+func upgradeScriptGroupResourceFromV0(ctx context.Context, prior scriptGroupResourceDataV0) (scriptGroupResourceData, diag.Diagnostics) {
+	return scriptGroupResourceData{
+		ProviderId:  prior.ProviderId,
+		Id:          prior.Id,
+		Uuid:        prior.Uuid,
+		AltID:       prior.AltID,
+		Name:        prior.Name,
+		Description: prior.Description,
+		Public:      prior.Public,
+		Tags:        types.MapNull(types.StringType),
+		TagsAll:     types.MapNull(types.StringType),
+	}, nil
+}
+
+// myscribae_script
+
+type scriptResourceDataV0 struct {
+	ProviderID       types.String `tfsdk:"provider_id"`
+	ScriptGroupID    types.String `tfsdk:"script_group_id"`
+	Id               types.String `tfsdk:"id"`
+	Uuid             types.String `tfsdk:"uuid"`
+	AltID            types.String `tfsdk:"alt_id"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	Recurrence       types.String `tfsdk:"recurrence"`
+	PriceInCents     types.Int64  `tfsdk:"price_in_cents"`
+	SlaSec           types.Int64  `tfsdk:"sla_sec"`
+	TokenLifetimeSec types.Int64  `tfsdk:"token_lifetime_sec"`
+	Public           types.Bool   `tfsdk:"public"`
+}
+
+func scriptResourceSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                 schema.StringAttribute{Computed: true},
+			"provider_id":        schema.StringAttribute{Required: true},
+			"script_group_id":    schema.StringAttribute{Required: true},
+			"alt_id":             schema.StringAttribute{Required: true},
+			"uuid":               schema.StringAttribute{Computed: true},
+			"name":               schema.StringAttribute{Required: true},
+			"description":        schema.StringAttribute{Required: true},
+			"recurrence":         schema.StringAttribute{Required: true},
+			"price_in_cents":     schema.Int64Attribute{Required: true},
+			"sla_sec":            schema.Int64Attribute{Required: true},
+			"token_lifetime_sec": schema.Int64Attribute{Required: true},
+			"public":             schema.BoolAttribute{Optional: true, Computed: true},
+		},
+	}
+}
+
+func (e *scriptResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: stateUpgrader(scriptResourceSchemaV0(), upgradeScriptResourceFromV0),
+	}
+}
+
+// upgradeScriptResourceFromV0 fills in the duration forms of the sla and token
+// lifetime, which v0 only stored in seconds
+func upgradeScriptResourceFromV0(ctx context.Context, prior scriptResourceDataV0) (scriptResourceData, diag.Diagnostics) {
+	return scriptResourceData{
+		ProviderID:       prior.ProviderID,
+		ScriptGroupID:    prior.ScriptGroupID,
+		Id:               prior.Id,
+		Uuid:             prior.Uuid,
+		AltID:            prior.AltID,
+		Name:             prior.Name,
+		Description:      prior.Description,
+		Recurrence:       prior.Recurrence,
+		PriceInCents:     prior.PriceInCents,
+		SlaSec:           prior.SlaSec,
+		Sla:              durationFromSeconds(prior.SlaSec),
+		TokenLifetimeSec: prior.TokenLifetimeSec,
+		TokenLifetime:    durationFromSeconds(prior.TokenLifetimeSec),
+		Public:           prior.Public,
+		Tags:             types.MapNull(types.StringType),
+		TagsAll:          types.MapNull(types.StringType),
+	}, nil
+}
+
+func durationFromSeconds(sec types.Int64) customtypes.Duration {
+	if sec.IsNull() || sec.IsUnknown() {
+		return customtypes.NewDurationNull()
+	}
+
+	return customtypes.NewDurationSeconds(sec.ValueInt64())
+}
@@ -0,0 +1,379 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/myscribae/myscribae-terraform-provider/customtypes"
+)
+
+func TestUpgradeProviderResourceFromV0(t *testing.T) {
+	tests := map[string]struct {
+		prior myscribaeProviderResourceDataV0
+	}{
+		"full": {
+			prior: myscribaeProviderResourceDataV0{
+				Id:             types.StringValue("prov-1"),
+				Name:           types.StringValue("acme"),
+				AltID:          types.StringValue("acme"),
+				Uuid:           types.StringValue("00000000-0000-0000-0000-000000000001"),
+				Description:    types.StringValue("scripts"),
+				LogoUrl:        types.StringValue("https://acme.test/logo.png"),
+				BannerUrl:      types.StringValue("https://acme.test/banner.png"),
+				Url:            types.StringValue("https://acme.test"),
+				Color:          types.StringValue("#ffffff"),
+				Public:         types.BoolValue(true),
+				AccountService: types.BoolValue(false),
+				SecretKey:      types.StringValue("secret"),
+				ApiKey:         types.StringValue("api"),
+			},
+		},
+		"null": {
+			prior: myscribaeProviderResourceDataV0{
+				Id:             types.StringValue("prov-1"),
+				Name:           types.StringValue("acme"),
+				AltID:          types.StringNull(),
+				Uuid:           types.StringNull(),
+				Description:    types.StringValue("scripts"),
+				LogoUrl:        types.StringNull(),
+				BannerUrl:      types.StringNull(),
+				Url:            types.StringNull(),
+				Color:          types.StringNull(),
+				Public:         types.BoolNull(),
+				AccountService: types.BoolNull(),
+				SecretKey:      types.StringNull(),
+				ApiKey:         types.StringNull(),
+			},
+		},
+		"unknown": {
+			prior: myscribaeProviderResourceDataV0{
+				Id:             types.StringUnknown(),
+				Name:           types.StringValue("acme"),
+				AltID:          types.StringNull(),
+				Uuid:           types.StringNull(),
+				Description:    types.StringValue("scripts"),
+				LogoUrl:        types.StringNull(),
+				BannerUrl:      types.StringNull(),
+				Url:            types.StringNull(),
+				Color:          types.StringUnknown(),
+				Public:         types.BoolUnknown(),
+				AccountService: types.BoolUnknown(),
+				SecretKey:      types.StringUnknown(),
+				ApiKey:         types.StringUnknown(),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := upgradeProviderResourceFromV0(context.Background(), test.prior)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			want := myscribaeProviderResourceData{
+				Id:             test.prior.Id,
+				Name:           test.prior.Name,
+				AltID:          test.prior.AltID,
+				Uuid:           test.prior.Uuid,
+				Description:    test.prior.Description,
+				LogoUrl:        test.prior.LogoUrl,
+				BannerUrl:      test.prior.BannerUrl,
+				Url:            test.prior.Url,
+				Color:          test.prior.Color,
+				Public:         test.prior.Public,
+				AccountService: test.prior.AccountService,
+				AdoptExisting:  types.BoolValue(false),
+				KeyManagement:  types.StringValue(keyManagementPreserve),
+				SecretKey:      test.prior.SecretKey,
+				ApiKey:         test.prior.ApiKey,
+				Tags:           types.MapNull(types.StringType),
+				TagsAll:        types.MapNull(types.StringType),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUpgradeScriptGroupResourceFromV0(t *testing.T) {
+	tests := map[string]struct {
+		prior scriptGroupResourceDataV0
+	}{
+		"full": {
+			prior: scriptGroupResourceDataV0{
+				ProviderId:  types.StringValue("prov-1"),
+				Id:          types.StringValue("group-1"),
+				Uuid:        types.StringValue("00000000-0000-0000-0000-000000000002"),
+				AltID:       types.StringValue("reports"),
+				Name:        types.StringValue("Reports"),
+				Description: types.StringValue("reporting scripts"),
+				Public:      types.BoolValue(true),
+			},
+		},
+		"null": {
+			prior: scriptGroupResourceDataV0{
+				ProviderId:  types.StringValue("prov-1"),
+				Id:          types.StringValue("group-1"),
+				Uuid:        types.StringNull(),
+				AltID:       types.StringValue("reports"),
+				Name:        types.StringValue("Reports"),
+				Description: types.StringValue("reporting scripts"),
+				Public:      types.BoolNull(),
+			},
+		},
+		"unknown": {
+			prior: scriptGroupResourceDataV0{
+				ProviderId:  types.StringValue("prov-1"),
+				Id:          types.StringUnknown(),
+				Uuid:        types.StringUnknown(),
+				AltID:       types.StringValue("reports"),
+				Name:        types.StringValue("Reports"),
+				Description: types.StringValue("reporting scripts"),
+				Public:      types.BoolUnknown(),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := upgradeScriptGroupResourceFromV0(context.Background(), test.prior)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			want := scriptGroupResourceData{
+				ProviderId:  test.prior.ProviderId,
+				Id:          test.prior.Id,
+				Uuid:        test.prior.Uuid,
+				AltID:       test.prior.AltID,
+				Name:        test.prior.Name,
+				Description: test.prior.Description,
+				Public:      test.prior.Public,
+				Tags:        types.MapNull(types.StringType),
+				TagsAll:     types.MapNull(types.StringType),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUpgradeScriptResourceFromV0(t *testing.T) {
+	tests := map[string]struct {
+		slaSec            types.Int64
+		tokenLifetimeSec  types.Int64
+		wantSla           customtypes.Duration
+		wantTokenLifetime customtypes.Duration
+	}{
+		"seconds": {
+			slaSec:            types.Int64Value(3600),
+			tokenLifetimeSec:  types.Int64Value(90),
+			wantSla:           customtypes.NewDurationValue("1h0m0s"),
+			wantTokenLifetime: customtypes.NewDurationValue("1m30s"),
+		},
+		"zero": {
+			slaSec:            types.Int64Value(0),
+			tokenLifetimeSec:  types.Int64Value(0),
+			wantSla:           customtypes.NewDurationValue("0s"),
+			wantTokenLifetime: customtypes.NewDurationValue("0s"),
+		},
+		"null": {
+			slaSec:            types.Int64Null(),
+			tokenLifetimeSec:  types.Int64Null(),
+			wantSla:           customtypes.NewDurationNull(),
+			wantTokenLifetime: customtypes.NewDurationNull(),
+		},
+		"unknown": {
+			slaSec:            types.Int64Unknown(),
+			tokenLifetimeSec:  types.Int64Unknown(),
+			wantSla:           customtypes.NewDurationNull(),
+			wantTokenLifetime: customtypes.NewDurationNull(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prior := scriptResourceDataV0{
+				ProviderID:       types.StringValue("prov-1"),
+				ScriptGroupID:    types.StringValue("reports"),
+				Id:               types.StringValue("script-1"),
+				Uuid:             types.StringNull(),
+				AltID:            types.StringValue("daily"),
+				Name:             types.StringValue("Daily"),
+				Description:      types.StringValue("daily report"),
+				Recurrence:       types.StringValue("daily"),
+				PriceInCents:     types.Int64Value(100),
+				SlaSec:           test.slaSec,
+				TokenLifetimeSec: test.tokenLifetimeSec,
+				Public:           types.BoolNull(),
+			}
+
+			got, diags := upgradeScriptResourceFromV0(context.Background(), prior)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			want := scriptResourceData{
+				ProviderID:       prior.ProviderID,
+				ScriptGroupID:    prior.ScriptGroupID,
+				Id:               prior.Id,
+				Uuid:             prior.Uuid,
+				AltID:            prior.AltID,
+				Name:             prior.Name,
+				Description:      prior.Description,
+				Recurrence:       prior.Recurrence,
+				PriceInCents:     prior.PriceInCents,
+				SlaSec:           test.slaSec,
+				Sla:              test.wantSla,
+				TokenLifetimeSec: test.tokenLifetimeSec,
+				TokenLifetime:    test.wantTokenLifetime,
+				Public:           prior.Public,
+				Tags:             types.MapNull(types.StringType),
+				TagsAll:          types.MapNull(types.StringType),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+// TestUpgradeResourceState upgrades state json written by v0 of each resource
+// through the provider server, as terraform does on the first plan after an
+// upgrade of the provider
+func TestUpgradeResourceState(t *testing.T) {
+	tests := map[string]struct {
+		typeName string
+		v0State  string
+		want     map[string]tftypes.Value
+	}{
+		"myscribae_provider": {
+			typeName: "myscribae_provider",
+			v0State: `{
+				"id": "prov-1",
+				"alt_id": null,
+				"uuid": null,
+				"name": "acme",
+				"description": "scripts",
+				"logo_url": null,
+				"banner_url": null,
+				"url": "https://acme.test",
+				"color": "#ffffff",
+				"public": true,
+				"account_service": false,
+				"secret_key": "secret",
+				"api_key": "api"
+			}`,
+			want: map[string]tftypes.Value{
+				"id":             tftypes.NewValue(tftypes.String, "prov-1"),
+				"alt_id":         tftypes.NewValue(tftypes.String, nil),
+				"url":            tftypes.NewValue(tftypes.String, "https://acme.test"),
+				"public":         tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+				"key_management": tftypes.NewValue(tftypes.String, keyManagementPreserve),
+				"secret_key":     tftypes.NewValue(tftypes.String, "secret"),
+				"api_key":        tftypes.NewValue(tftypes.String, "api"),
+				"tags":           tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+				"tags_all":       tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+			},
+		},
+		"myscribae_script_group": {
+			typeName: "myscribae_script_group",
+			v0State: `{
+				"id": "group-1",
+				"provider_id": "prov-1",
+				"uuid": "00000000-0000-0000-0000-000000000002",
+				"alt_id": "reports",
+				"name": "Reports",
+				"description": "reporting scripts",
+				"public": false
+			}`,
+			want: map[string]tftypes.Value{
+				"id":          tftypes.NewValue(tftypes.String, "group-1"),
+				"provider_id": tftypes.NewValue(tftypes.String, "prov-1"),
+				"alt_id":      tftypes.NewValue(tftypes.String, "reports"),
+				"public":      tftypes.NewValue(tftypes.Bool, false),
+				"tags":        tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+			},
+		},
+		"myscribae_script": {
+			typeName: "myscribae_script",
+			v0State: `{
+				"id": "script-1",
+				"provider_id": "prov-1",
+				"script_group_id": "reports",
+				"alt_id": "daily",
+				"uuid": "00000000-0000-0000-0000-000000000003",
+				"name": "Daily",
+				"description": "daily report",
+				"recurrence": "daily",
+				"price_in_cents": 100,
+				"sla_sec": 3600,
+				"token_lifetime_sec": 86400,
+				"public": true
+			}`,
+			want: map[string]tftypes.Value{
+				"id":                 tftypes.NewValue(tftypes.String, "script-1"),
+				"script_group_id":    tftypes.NewValue(tftypes.String, "reports"),
+				"sla_sec":            tftypes.NewValue(tftypes.Number, 3600),
+				"sla":                tftypes.NewValue(tftypes.String, "1h0m0s"),
+				"token_lifetime_sec": tftypes.NewValue(tftypes.Number, 86400),
+				"token_lifetime":     tftypes.NewValue(tftypes.String, "24h0m0s"),
+				"tags_all":           tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: test.typeName,
+				Version:  0,
+				RawState: &tfprotov6.RawState{JSON: []byte(test.v0State)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range resp.Diagnostics {
+				if d.Severity == tfprotov6.DiagnosticSeverityError {
+					t.Fatalf("%s: %s", d.Summary, d.Detail)
+				}
+			}
+
+			objectType := schemas.ResourceSchemas[test.typeName].ValueType()
+			state, err := resp.UpgradedState.Unmarshal(objectType)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var attrs map[string]tftypes.Value
+			if err := state.As(&attrs); err != nil {
+				t.Fatal(err)
+			}
+			for attr, want := range test.want {
+				if got := attrs[attr]; !got.Equal(want) {
+					t.Errorf("%s: got %s, want %s", attr, got, want)
+				}
+			}
+		})
+	}
+}
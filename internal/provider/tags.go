@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/myscribae/myscribae-sdk-go/gql"
+	"github.com/myscribae/myscribae-sdk-go/provider"
+)
+
+// The sdk does not model tags and the api publishes no schema for them, so
+// this file assumes one: a tags field on provider_self, script_group and
+// script that returns a json object of strings, read with queries of their
+// own, and a tags key in the json changes of the edit mutations that replaces
+// them whole. The object is one scalar, hence the scalar tag. An api without
+// the field fails these queries, which readResourceTags tolerates until tags
+// are configured
+
+type providerTagsQuery struct {
+	ProviderSelf struct {
+		Tags map[string]string `graphql:"tags" scalar:"true"`
+	} `graphql:"provider_self(id:$id)"`
+}
+
+type scriptGroupTagsQuery struct {
+	ProviderSelf struct {
+		ScriptGroup struct {
+			Tags map[string]string `graphql:"tags" scalar:"true"`
+		} `graphql:"script_group(id:$id)"`
+	} `graphql:"provider_self(id:$provider_id)"`
+}
+
+type scriptTagsQuery struct {
+	ProviderSelf struct {
+		ScriptGroup struct {
+			Script struct {
+				Tags map[string]string `graphql:"tags" scalar:"true"`
+			} `graphql:"script(id:$id)"`
+		} `graphql:"script_group(id:$script_group_id)"`
+	} `graphql:"provider_self(id:$provider_id)"`
+}
+
+// readProviderTags reads the tags of prov through the read cache
+func (p *myScribaeProvider) readProviderTags(ctx context.Context, prov *provider.Provider) (map[string]string, error) {
+	tags, err := cachedRead(ctx, &p.reads, fmt.Sprintf("tags/provider/%s", prov.ID()), func(ctx context.Context) (*map[string]string, error) {
+		var query providerTagsQuery
+		if err := prov.Client.Query(ctx, &query, map[string]interface{}{
+			"id": prov.ID(),
+		}); err != nil {
+			return nil, err
+		}
+
+		return &query.ProviderSelf.Tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return *tags, nil
+}
+
+// readScriptGroupTags reads the tags of scriptGroup through the read cache
+func (p *myScribaeProvider) readScriptGroupTags(ctx context.Context, scriptGroup *provider.ScriptGroup) (map[string]string, error) {
+	tags, err := cachedRead(ctx, &p.reads, fmt.Sprintf("tags/script_group/%s/%s", scriptGroup.Provider.ID(), scriptGroup.AltID), func(ctx context.Context) (*map[string]string, error) {
+		var query scriptGroupTagsQuery
+		if err := scriptGroup.Provider.Client.Query(ctx, &query, map[string]interface{}{
+			"provider_id": scriptGroup.Provider.ID(),
+			"id":          scriptGroup.AltID,
+		}); err != nil {
+			return nil, err
+		}
+
+		return &query.ProviderSelf.ScriptGroup.Tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return *tags, nil
+}
+
+// readScriptTags reads the tags of script through the read cache
+func (p *myScribaeProvider) readScriptTags(ctx context.Context, script *provider.Script) (map[string]string, error) {
+	tags, err := cachedRead(ctx, &p.reads, fmt.Sprintf("tags/script/%s/%s/%s", script.Provider.ID(), script.ScriptGroupID, script.AltID), func(ctx context.Context) (*map[string]string, error) {
+		var query scriptTagsQuery
+		if err := script.Provider.Client.Query(ctx, &query, map[string]interface{}{
+			"provider_id":     script.Provider.ID(),
+			"script_group_id": script.ScriptGroupID,
+			"id":              script.AltID,
+		}); err != nil {
+			return nil, err
+		}
+
+		return &query.ProviderSelf.ScriptGroup.Script.Tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return *tags, nil
+}
+
+// setProviderTags replaces the tags of prov
+func setProviderTags(ctx context.Context, prov *provider.Provider, tags map[string]string) error {
+	changes, err := tagsChanges(tags)
+	if err != nil {
+		return err
+	}
+
+	var mutation gql.EditProviderProfile
+	return prov.Client.Mutate(ctx, &mutation, map[string]interface{}{
+		"id":      prov.ID(),
+		"changes": changes,
+	})
+}
+
+// setScriptGroupTags replaces the tags of scriptGroup
+func setScriptGroupTags(ctx context.Context, scriptGroup *provider.ScriptGroup, tags map[string]string) error {
+	changes, err := tagsChanges(tags)
+	if err != nil {
+		return err
+	}
+
+	var mutation gql.EditScriptGroup
+	return scriptGroup.Provider.Client.Mutate(ctx, &mutation, map[string]interface{}{
+		"provider_id": scriptGroup.Provider.ID(),
+		"id":          scriptGroup.AltID,
+		"changes":     changes,
+	})
+}
+
+// setScriptTags replaces the tags of script
+func setScriptTags(ctx context.Context, script *provider.Script, tags map[string]string) error {
+	changes, err := tagsChanges(tags)
+	if err != nil {
+		return err
+	}
+
+	var mutation gql.EditScript
+	return script.Provider.Client.Mutate(ctx, &mutation, map[string]interface{}{
+		"provider_id":     script.Provider.ID(),
+		"script_group_id": script.ScriptGroupID,
+		"id":              script.AltID,
+		"changes":         changes,
+	})
+}
+
+// tagsChanges sends no tags as an empty object, which clears them
+func tagsChanges(tags map[string]string) (string, error) {
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	changes, err := json.Marshal(map[string]interface{}{
+		"tags": tags,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(changes), nil
+}
+
+// tagsMap returns the elements of tags, which are all known once planned
+func tagsMap(ctx context.Context, tags types.Map) (map[string]string, diag.Diagnostics) {
+	result := map[string]string{}
+	if tags.IsNull() || tags.IsUnknown() {
+		return result, nil
+	}
+
+	diags := tags.ElementsAs(ctx, &result, false)
+	return result, diags
+}
+
+// tagsValue returns the tags read from the api as they would be configured.
+// The api does not tell no tags from an empty map, so prior decides which of
+// the two is kept
+func tagsValue(ctx context.Context, tags map[string]string, prior types.Map) (types.Map, diag.Diagnostics) {
+	if len(tags) == 0 && prior.IsNull() {
+		return types.MapNull(types.StringType), nil
+	}
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, tags)
+}
+
+// dataSourceTags returns the tags read for a data source. A failed read only
+// warns and leaves the tags null, so data sources keep working against an api
+// without tags
+func dataSourceTags(ctx context.Context, tags map[string]string, err error) (types.Map, diag.Diagnostics) {
+	if err != nil {
+		tflog.Warn(ctx, "failed to read tags", map[string]interface{}{
+			"error": err,
+		})
+		return types.MapNull(types.StringType), nil
+	}
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, tags)
+}
+
+// tagsAll merges tags over the default_tags of the provider, the value tags_all
+// is planned and applied as. It is unknown while any tag is
+func (p *myScribaeProvider) tagsAll(ctx context.Context, tags types.Map) (types.Map, diag.Diagnostics) {
+	if tags.IsUnknown() {
+		return types.MapUnknown(types.StringType), nil
+	}
+	if tags.IsNull() && len(p.DefaultTags) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+
+	merged := map[string]string{}
+	for k, v := range p.DefaultTags {
+		merged[k] = v
+	}
+	for k, v := range tags.Elements() {
+		value, ok := v.(types.String)
+		if !ok || value.IsUnknown() {
+			return types.MapUnknown(types.StringType), nil
+		}
+		merged[k] = value.ValueString()
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, merged)
+}
+
+// refreshedTags splits the tags read from the api into tags and tags_all. A
+// tag equal to the default tag of the same key is left out of tags, unless
+// tags had it before, so the defaults never show up as a diff
+func (p *myScribaeProvider) refreshedTags(ctx context.Context, remote map[string]string, priorTags types.Map) (types.Map, types.Map, diag.Diagnostics) {
+	prior, diags := tagsMap(ctx, priorTags)
+	if diags.HasError() {
+		return priorTags, types.MapNull(types.StringType), diags
+	}
+
+	own := map[string]string{}
+	for k, v := range remote {
+		if _, ok := prior[k]; ok {
+			own[k] = v
+			continue
+		}
+		if defaultValue, ok := p.DefaultTags[k]; !ok || defaultValue != v {
+			own[k] = v
+		}
+	}
+
+	tags, d := tagsValue(ctx, own, priorTags)
+	diags.Append(d...)
+
+	// tags_all is planned null only while neither tags nor default_tags are
+	// set, which an empty map read back must match
+	tagsAllPrior := types.MapNull(types.StringType)
+	if !tags.IsNull() || len(p.DefaultTags) > 0 {
+		tagsAllPrior = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	}
+	tagsAll, d := tagsValue(ctx, remote, tagsAllPrior)
+	diags.Append(d...)
+
+	return tags, tagsAll, diags
+}
+
+// tagsNotSetWarning reports tags that could not be set on a resource just
+// created. The resource is kept rather than tainted, which would replace it on
+// every apply, and its tags are read back on the next refresh, so the next
+// apply sets them again
+func tagsNotSetWarning(diags *diag.Diagnostics, noun string, err error) {
+	diags.AddAttributeWarning(
+		path.Root("tags"),
+		fmt.Sprintf("failed to set %s tags", noun),
+		fmt.Sprintf("The %s was created, but its tags could not be set: %s. The next refresh reads its tags back from the api and the next apply sets them again.", noun, err),
+	)
+}
+
+// importedKey is set in the private state of an imported resource, so the
+// read that follows the import takes its tags from the api
+const importedKey = "imported"
+
+// markImported sets importedKey in the private state of an import
+func markImported(ctx context.Context, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedKey, []byte("true"))...)
+}
+
+// readResourceTags reads the tags of a resource and splits them into tags and
+// tags_all for its state. A resource with tags, or one just imported, fails
+// when they cannot be read. Any other resource only warns and keeps no tags,
+// so it keeps working against an api without tags
+func (p *myScribaeProvider) readResourceTags(ctx context.Context, noun string, read func(context.Context) (map[string]string, error), priorTags types.Map, priorTagsAll types.Map, req resource.ReadRequest, resp *resource.ReadResponse) (types.Map, types.Map) {
+	imported, diags := req.Private.GetKey(ctx, importedKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return priorTags, priorTagsAll
+	}
+
+	remote, err := read(ctx)
+	if err != nil {
+		if imported != nil || !priorTags.IsNull() || !priorTagsAll.IsNull() {
+			resp.Diagnostics.AddError(fmt.Sprintf("failed to get %s tags", noun), err.Error())
+			return priorTags, priorTagsAll
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("failed to read %s tags", noun), map[string]interface{}{
+			"error": err,
+		})
+		return types.MapNull(types.StringType), types.MapNull(types.StringType)
+	}
+
+	tags, tagsAll, diags := p.refreshedTags(ctx, remote, priorTags)
+	resp.Diagnostics.Append(diags...)
+	if imported != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedKey, nil)...)
+	}
+
+	return tags, tagsAll
+}
+
+// planTagsAll plans tags_all from the configured tags and the default_tags of
+// the provider, so an unchanged merge plans no change
+func planTagsAll(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, prov *myScribaeProvider) {
+	if prov == nil {
+		return
+	}
+
+	var tags types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags"), &tags)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagsAll, diags := prov.tagsAll(ctx, tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), tagsAll)...)
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenExpirySkew is how long before it expires a token is replaced, so a
+// token is never sent just as it expires
+const tokenExpirySkew = time.Minute
+
+// tokenSource supplies the api token sent with every request
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// refreshableTokenSource is a tokenSource whose tokens can be replaced early,
+// such as when the api rejects one
+type refreshableTokenSource interface {
+	tokenSource
+	Expire(token string)
+}
+
+// staticToken is an api token that never changes
+type staticToken string
+
+func (t staticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// commandToken runs a command that prints a json {"token", "expires_at"}
+// object and caches the token until it expires. Without expires_at the token
+// is kept for the lifetime of the provider
+type commandToken struct {
+	command []string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+var _ refreshableTokenSource = (*commandToken)(nil)
+
+func newCommandToken(command []string) *commandToken {
+	return &commandToken{
+		command: command,
+	}
+}
+
+func (c *commandToken) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.expiresAt.IsZero() || time.Now().Add(tokenExpirySkew).Before(c.expiresAt)) {
+		return c.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.command[0], c.command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("token command %q failed: %w: %s", c.command[0], err, msg)
+		}
+		return "", fmt.Errorf("token command %q failed: %w", c.command[0], err)
+	}
+
+	var output struct {
+		Token     string     `json:"token"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return "", fmt.Errorf("token command %q printed invalid json, expected {\"token\": \"...\", \"expires_at\": \"<RFC 3339 time>\"}: %w", c.command[0], err)
+	}
+	if output.Token == "" {
+		return "", fmt.Errorf("token command %q printed no token", c.command[0])
+	}
+
+	c.token = output.Token
+	c.expiresAt = time.Time{}
+	if output.ExpiresAt != nil {
+		c.expiresAt = *output.ExpiresAt
+	}
+
+	tflog.Debug(ctx, "ran token command", map[string]interface{}{
+		"command":    c.command[0],
+		"expires_at": c.expiresAt,
+	})
+
+	return c.token, nil
+}
+
+// ExpiresAt returns when the cached token expires, or the zero time when the
+// command printed no expires_at
+func (c *commandToken) ExpiresAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.expiresAt
+}
+
+// Expire drops token, so the command runs again for the next request
+func (c *commandToken) Expire(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = ""
+	}
+}
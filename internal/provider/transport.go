@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// transportOptions configure how the api client reaches the MyScribae API,
+// for networks with a proxy, a private certificate authority or mutual tls
+type transportOptions struct {
+	ProxyUrl           string
+	CaCertPem          string
+	CaCertFile         string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+// newHttpTransport builds the transport under the api client. Without
+// proxy_url the proxy comes from HTTPS_PROXY, HTTP_PROXY and NO_PROXY as for
+// any go program
+func newHttpTransport(opts transportOptions) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		TLSClientConfig: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: opts.InsecureSkipVerify,
+		},
+	}
+
+	if opts.ProxyUrl != "" {
+		proxyUrl, err := url.Parse(opts.ProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	caCert := []byte(opts.CaCertPem)
+	if opts.CaCertFile != "" {
+		var err error
+		if caCert, err = os.ReadFile(opts.CaCertFile); err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+	}
+	if len(caCert) > 0 {
+		// the private authority is trusted as well as the system ones, so a
+		// proxy that only intercepts some hosts keeps working
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("the ca certificate holds no PEM encoded certificates")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if opts.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.ClientCert), []byte(opts.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client_cert or client_key: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return transport, nil
+}
+
+// headerTransport adds extra_headers and the User-Agent to every request. The
+// authentication headers are set after them, so they cannot be replaced
+type headerTransport struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	r := req.Clone(req.Context())
+	for name, value := range t.headers {
+		r.Header.Set(name, value)
+	}
+
+	return t.next.RoundTrip(r)
+}
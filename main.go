@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	provider "github.com/myscribae/myscribae-terraform-provider/internal/provider"
@@ -31,17 +32,34 @@ var (
 	// https://goreleaser.com/cookbooks/using-main.version/
 )
 
+// TODO: Update this string with the published name of your provider.
+// Also update the tfplugindocs generate command to either remove the
+// -provider-name flag or set its value to the updated provider name.
+const providerAddress = "registry.terraform.io/myscribae/myscribae"
+
 func main() {
-	var debug bool
+	var debug, schemaJson bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&schemaJson, "schema-json", false, "print the provider schemas as terraform providers schema -json does, then exit")
 	flag.Parse()
 
+	if schemaJson {
+		if err := writeSchemaJson(os.Stdout); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	if flag.Arg(0) == "export" {
+		if err := runExport(flag.Args()[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	opts := providerserver.ServeOpts{
-		// TODO: Update this string with the published name of your provider.
-		// Also update the tfplugindocs generate command to either remove the
-		// -provider-name flag or set its value to the updated provider name.
-		Address: "registry.terraform.io/myscribae/myscribae",
+		Address: providerAddress,
 		Debug:   debug,
 	}
 
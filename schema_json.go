@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	provider "github.com/myscribae/myscribae-terraform-provider/internal/provider"
+)
+
+// The json layout of terraform providers schema -json, so tools reading it
+// need no changes
+
+type schemasJson struct {
+	FormatVersion   string                        `json:"format_version"`
+	ProviderSchemas map[string]providerSchemaJson `json:"provider_schemas"`
+}
+
+type providerSchemaJson struct {
+	Provider          schemaJson              `json:"provider"`
+	ResourceSchemas   map[string]schemaJson   `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]schemaJson   `json:"data_source_schemas,omitempty"`
+	Functions         map[string]functionJson `json:"functions,omitempty"`
+}
+
+type schemaJson struct {
+	Version int64     `json:"version"`
+	Block   blockJson `json:"block"`
+}
+
+type blockJson struct {
+	Attributes      map[string]attributeJson `json:"attributes,omitempty"`
+	BlockTypes      map[string]blockTypeJson `json:"block_types,omitempty"`
+	Description     string                   `json:"description,omitempty"`
+	DescriptionKind string                   `json:"description_kind"`
+	Deprecated      bool                     `json:"deprecated,omitempty"`
+}
+
+type attributeJson struct {
+	Type            json.RawMessage `json:"type,omitempty"`
+	NestedType      *nestedTypeJson `json:"nested_type,omitempty"`
+	Description     string          `json:"description,omitempty"`
+	DescriptionKind string          `json:"description_kind"`
+	Deprecated      bool            `json:"deprecated,omitempty"`
+	Required        bool            `json:"required,omitempty"`
+	Optional        bool            `json:"optional,omitempty"`
+	Computed        bool            `json:"computed,omitempty"`
+	Sensitive       bool            `json:"sensitive,omitempty"`
+}
+
+type nestedTypeJson struct {
+	Attributes  map[string]attributeJson `json:"attributes"`
+	NestingMode string                   `json:"nesting_mode"`
+}
+
+type blockTypeJson struct {
+	NestingMode string    `json:"nesting_mode"`
+	Block       blockJson `json:"block"`
+	MinItems    int64     `json:"min_items,omitempty"`
+	MaxItems    int64     `json:"max_items,omitempty"`
+}
+
+type functionJson struct {
+	Description        string                  `json:"description,omitempty"`
+	Summary            string                  `json:"summary,omitempty"`
+	DeprecationMessage string                  `json:"deprecation_message,omitempty"`
+	ReturnType         json.RawMessage         `json:"return_type"`
+	Parameters         []functionParameterJson `json:"parameters,omitempty"`
+	VariadicParameter  *functionParameterJson  `json:"variadic_parameter,omitempty"`
+}
+
+type functionParameterJson struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	IsNullable  bool            `json:"is_nullable,omitempty"`
+	Type        json.RawMessage `json:"type"`
+}
+
+// writeSchemaJson prints the schemas of the provider, its resources, data
+// sources and functions as terraform providers schema -json does, without
+// terraform or network access
+func writeSchemaJson(w io.Writer) error {
+	server := providerserver.NewProtocol6(provider.New(version)())()
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errs = append(errs, fmt.Errorf("%s: %s", d.Summary, d.Detail))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	schemas := providerSchemaJson{
+		Provider:          convertSchema(resp.Provider),
+		ResourceSchemas:   map[string]schemaJson{},
+		DataSourceSchemas: map[string]schemaJson{},
+		Functions:         map[string]functionJson{},
+	}
+	for name, schema := range resp.ResourceSchemas {
+		schemas.ResourceSchemas[name] = convertSchema(schema)
+	}
+	for name, schema := range resp.DataSourceSchemas {
+		schemas.DataSourceSchemas[name] = convertSchema(schema)
+	}
+	for name, function := range resp.Functions {
+		schemas.Functions[name] = convertFunction(function)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schemasJson{
+		FormatVersion: "1.0",
+		ProviderSchemas: map[string]providerSchemaJson{
+			providerAddress: schemas,
+		},
+	})
+}
+
+func convertSchema(schema *tfprotov6.Schema) schemaJson {
+	if schema == nil {
+		return schemaJson{Block: blockJson{DescriptionKind: "plain"}}
+	}
+
+	return schemaJson{
+		Version: schema.Version,
+		Block:   convertBlock(schema.Block),
+	}
+}
+
+func convertBlock(block *tfprotov6.SchemaBlock) blockJson {
+	converted := blockJson{
+		Attributes:      convertAttributes(block.Attributes),
+		Description:     block.Description,
+		DescriptionKind: descriptionKind(block.DescriptionKind),
+		Deprecated:      block.Deprecated,
+	}
+
+	if len(block.BlockTypes) > 0 {
+		converted.BlockTypes = map[string]blockTypeJson{}
+	}
+	for _, nested := range block.BlockTypes {
+		converted.BlockTypes[nested.TypeName] = blockTypeJson{
+			NestingMode: blockNestingModes[nested.Nesting],
+			Block:       convertBlock(nested.Block),
+			MinItems:    nested.MinItems,
+			MaxItems:    nested.MaxItems,
+		}
+	}
+
+	return converted
+}
+
+func convertAttributes(attributes []*tfprotov6.SchemaAttribute) map[string]attributeJson {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	converted := map[string]attributeJson{}
+	for _, attribute := range attributes {
+		a := attributeJson{
+			Description:     attribute.Description,
+			DescriptionKind: descriptionKind(attribute.DescriptionKind),
+			Deprecated:      attribute.Deprecated,
+			Required:        attribute.Required,
+			Optional:        attribute.Optional,
+			Computed:        attribute.Computed,
+			Sensitive:       attribute.Sensitive,
+		}
+		if attribute.Type != nil {
+			a.Type = typeJson(attribute.Type)
+		}
+		if attribute.NestedType != nil {
+			a.NestedType = &nestedTypeJson{
+				Attributes:  convertAttributes(attribute.NestedType.Attributes),
+				NestingMode: objectNestingModes[attribute.NestedType.Nesting],
+			}
+		}
+		converted[attribute.Name] = a
+	}
+
+	return converted
+}
+
+func convertFunction(function *tfprotov6.Function) functionJson {
+	converted := functionJson{
+		Description:        function.Description,
+		Summary:            function.Summary,
+		DeprecationMessage: function.DeprecationMessage,
+	}
+	if function.Return != nil {
+		converted.ReturnType = typeJson(function.Return.Type)
+	}
+	for _, parameter := range function.Parameters {
+		converted.Parameters = append(converted.Parameters, convertParameter(parameter))
+	}
+	if function.VariadicParameter != nil {
+		variadic := convertParameter(function.VariadicParameter)
+		converted.VariadicParameter = &variadic
+	}
+
+	return converted
+}
+
+func convertParameter(parameter *tfprotov6.FunctionParameter) functionParameterJson {
+	return functionParameterJson{
+		Name:        parameter.Name,
+		Description: parameter.Description,
+		IsNullable:  parameter.AllowNullValue,
+		Type:        typeJson(parameter.Type),
+	}
+}
+
+// typeJson renders a type as terraform does, such as "string" or
+// ["list","string"]
+func typeJson(t tftypes.Type) json.RawMessage {
+	//nolint:staticcheck // MarshalJSON is the encoding terraform uses for types
+	b, err := t.MarshalJSON()
+	if err != nil {
+		return json.RawMessage(`"dynamic"`)
+	}
+
+	return b
+}
+
+func descriptionKind(kind tfprotov6.StringKind) string {
+	if kind == tfprotov6.StringKindMarkdown {
+		return "markdown"
+	}
+
+	return "plain"
+}
+
+var blockNestingModes = map[tfprotov6.SchemaNestedBlockNestingMode]string{
+	tfprotov6.SchemaNestedBlockNestingModeSingle: "single",
+	tfprotov6.SchemaNestedBlockNestingModeList:   "list",
+	tfprotov6.SchemaNestedBlockNestingModeSet:    "set",
+	tfprotov6.SchemaNestedBlockNestingModeMap:    "map",
+	tfprotov6.SchemaNestedBlockNestingModeGroup:  "group",
+}
+
+var objectNestingModes = map[tfprotov6.SchemaObjectNestingMode]string{
+	tfprotov6.SchemaObjectNestingModeSingle: "single",
+	tfprotov6.SchemaObjectNestingModeList:   "list",
+	tfprotov6.SchemaObjectNestingModeSet:    "set",
+	tfprotov6.SchemaObjectNestingModeMap:    "map",
+}
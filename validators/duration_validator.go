@@ -0,0 +1,57 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type durationValidator struct {
+	min time.Duration
+	max time.Duration
+}
+
+var _ validator.String = (*durationValidator)(nil)
+
+// NewDurationValidator checks a go duration string is between min and max
+func NewDurationValidator(min time.Duration, max time.Duration) validator.String {
+	return &durationValidator{
+		min: min,
+		max: max,
+	}
+}
+
+func (u *durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	val, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		// reported by the duration type
+		return
+	}
+
+	if val%time.Second != 0 {
+		resp.Diagnostics.AddError("invalid duration", "duration must be a whole number of seconds")
+		return
+	}
+
+	if val < u.min || val > u.max {
+		resp.Diagnostics.AddError(
+			"invalid duration",
+			fmt.Sprintf("duration must be between %s and %s, got %s", u.min, u.max, val),
+		)
+		return
+	}
+}
+
+func (u *durationValidator) Description(context.Context) string {
+	return fmt.Sprintf("value must be a duration between %s and %s", u.min, u.max)
+}
+
+func (u *durationValidator) MarkdownDescription(context.Context) string {
+	return fmt.Sprintf("value must be a duration between `%s` and `%s`", u.min, u.max)
+}
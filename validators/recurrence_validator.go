@@ -2,25 +2,33 @@ package validators
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
 
-type recurrenceValidator struct{}
+type recurrenceValidator struct {
+	values []string
+}
 
 var _ validator.String = (*recurrenceValidator)(nil)
 
-func NewRecurrenceValidator() validator.String {
-	return &recurrenceValidator{}
+var recurrenceRegex = regexp.MustCompile(`^[a-z]+$`)
+
+// NewRecurrenceValidator checks a recurrence against values. Without values,
+// which are only known once the provider is configured, it checks the shape
+// of the recurrence and leaves membership to the api
+func NewRecurrenceValidator(values ...string) validator.String {
+	return &recurrenceValidator{
+		values: values,
+	}
 }
 
 func (u *recurrenceValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
-	// valid recurrences
-	validRecurrences := []string{
-		"lifetime",
-		"yearly",
-		"monthly",
-		"weekly",
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
 	}
 
 	val := req.ConfigValue.ValueString()
@@ -30,24 +38,31 @@ func (u *recurrenceValidator) ValidateString(ctx context.Context, req validator.
 		return
 	}
 
-	valid := false
-	for _, r := range validRecurrences {
-		if r == val {
-			valid = true
-			break
+	if len(u.values) == 0 {
+		if !recurrenceRegex.MatchString(val) {
+			resp.Diagnostics.AddError("invalid recurrence", "recurrence must be a lower case word")
 		}
+		return
 	}
 
-	if !valid {
-		resp.Diagnostics.AddError("invalid recurrence", "recurrence must be one of lifetime, yearly, monthly, weekly")
+	if !contains(u.values, val) {
+		resp.Diagnostics.AddError("invalid recurrence", fmt.Sprintf("recurrence must be one of %s", strings.Join(u.values, ", ")))
 		return
 	}
 }
 
 func (u *recurrenceValidator) Description(context.Context) string {
-	return "Validates a recurrence"
+	if len(u.values) == 0 {
+		return "Validates a recurrence"
+	}
+
+	return "value must be one of " + joinQuoted(u.values, func(s string) string { return s })
 }
 
 func (u *recurrenceValidator) MarkdownDescription(context.Context) string {
-	return "Validates a recurrence"
+	if len(u.values) == 0 {
+		return "Validates a recurrence"
+	}
+
+	return "value must be one of " + joinQuoted(u.values, func(s string) string { return "`" + s + "`" })
 }
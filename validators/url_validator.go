@@ -4,38 +4,211 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
 
+// DefaultUrlSchemes are the schemes accepted when no scheme allowlist is given
+var DefaultUrlSchemes = []string{"https"}
+
+// DeprecatedUrlSchemes are still accepted when no scheme allowlist is given,
+// with a warning, as urls using them were valid before the scheme check
+var DeprecatedUrlSchemes = []string{"http"}
+
+// LocalUrlHosts are hosts only reachable from the machine itself, which make
+// no sense in a public profile
+var LocalUrlHosts = []string{"localhost", "127.0.0.1", "0.0.0.0", "::1"}
+
+// ImageExtensions are the file extensions accepted by RequireImageExtension
+var ImageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp"}
+
 type urlValidator struct {
 	Required bool
+
+	schemes           []string
+	deprecatedSchemes []string
+	allowedHosts      []string
+	deniedHosts       []string
+	extensions        []string
+}
+
+var _ validator.String = (*urlValidator)(nil)
+
+// UrlValidatorOption configures the constraints checked by a url validator
+type UrlValidatorOption func(*urlValidator)
+
+// AllowSchemes replaces the default scheme allowlist (https, and http with a
+// deprecation warning)
+func AllowSchemes(schemes ...string) UrlValidatorOption {
+	return func(u *urlValidator) {
+		u.schemes = lowerAll(schemes)
+		u.deprecatedSchemes = nil
+	}
+}
+
+// AllowHosts only accepts urls whose host is, or is a subdomain of, one of hosts
+func AllowHosts(hosts ...string) UrlValidatorOption {
+	return func(u *urlValidator) {
+		u.allowedHosts = lowerAll(hosts)
+	}
+}
+
+// DenyHosts rejects urls whose host is, or is a subdomain of, one of hosts
+func DenyHosts(hosts ...string) UrlValidatorOption {
+	return func(u *urlValidator) {
+		u.deniedHosts = lowerAll(hosts)
+	}
 }
 
-func NewUrlValidator(required bool) validator.String {
-	return &urlValidator{
-		Required: required,
+// RequireImageExtension only accepts urls whose path ends in one of ImageExtensions
+func RequireImageExtension() UrlValidatorOption {
+	return func(u *urlValidator) {
+		u.extensions = ImageExtensions
 	}
 }
 
+func NewUrlValidator(required bool, opts ...UrlValidatorOption) validator.String {
+	u := &urlValidator{
+		Required:          required,
+		schemes:           DefaultUrlSchemes,
+		deprecatedSchemes: DeprecatedUrlSchemes,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
 func (u *urlValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() {
+		return
+	}
+
 	val := req.ConfigValue.ValueStringPointer()
 
-	if val != nil && *val != "" {
-		_, err := url.ParseRequestURI(*val)
-		if err != nil {
-			resp.Diagnostics.AddError("invalid url", fmt.Sprintf("invalid url: %s", err.Error()))
-			return
+	if val == nil || *val == "" {
+		if u.Required {
+			resp.Diagnostics.AddError("url cannot be empty", "url provided is empty")
 		}
-	} else if u.Required {
-		resp.Diagnostics.AddError("url cannot be empty", "url provided is empty")
+		return
+	}
+
+	parsed, err := url.Parse(*val)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid url", fmt.Sprintf("invalid url: %s", err.Error()))
+		return
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	deprecated := contains(u.deprecatedSchemes, scheme)
+	if !contains(u.schemes, scheme) && !deprecated {
+		resp.Diagnostics.AddError(
+			"invalid url",
+			fmt.Sprintf("url scheme must be one of %s, got %q", strings.Join(u.schemes, ", "), parsed.Scheme),
+		)
+		return
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		resp.Diagnostics.AddError("invalid url", "url must include a host")
+		return
+	}
+
+	if len(u.allowedHosts) > 0 && !matchesHost(u.allowedHosts, host) {
+		resp.Diagnostics.AddError(
+			"invalid url",
+			fmt.Sprintf("url host must be one of %s, got %q", strings.Join(u.allowedHosts, ", "), host),
+		)
+		return
+	}
+
+	if matchesHost(u.deniedHosts, host) {
+		resp.Diagnostics.AddError("invalid url", fmt.Sprintf("url host %q is not allowed", host))
+		return
+	}
+
+	if len(u.extensions) > 0 && !contains(u.extensions, strings.ToLower(path.Ext(parsed.Path))) {
+		resp.Diagnostics.AddError(
+			"invalid url",
+			fmt.Sprintf("url path must end in one of %s", strings.Join(u.extensions, ", ")),
+		)
+		return
+	}
+
+	if deprecated {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"deprecated url scheme",
+			fmt.Sprintf("%s urls are deprecated and will be rejected in a future release, use one of %s instead, got %q", parsed.Scheme, strings.Join(u.schemes, ", "), *val),
+		)
 	}
 }
 
 func (u *urlValidator) Description(context.Context) string {
-	return "Validates a url"
+	return u.describe(func(s string) string { return s })
 }
 
 func (u *urlValidator) MarkdownDescription(context.Context) string {
-	return "Validates a url"
+	return u.describe(func(s string) string { return "`" + s + "`" })
+}
+
+func (u *urlValidator) describe(quote func(string) string) string {
+	desc := "value must be a url with scheme " + joinQuoted(u.schemes, quote)
+	if len(u.deprecatedSchemes) > 0 {
+		desc += " (" + joinQuoted(u.deprecatedSchemes, quote) + " is deprecated)"
+	}
+	if len(u.allowedHosts) > 0 {
+		desc += ", host in " + joinQuoted(u.allowedHosts, quote)
+	}
+	if len(u.deniedHosts) > 0 {
+		desc += ", host not in " + joinQuoted(u.deniedHosts, quote)
+	}
+	if len(u.extensions) > 0 {
+		desc += ", path ending in " + joinQuoted(u.extensions, quote)
+	}
+
+	return desc
+}
+
+// matchesHost reports whether host equals, or is a subdomain of, any of hosts
+func matchesHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, val string) bool {
+	for _, v := range values {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lowerAll(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = strings.ToLower(v)
+	}
+
+	return result
+}
+
+func joinQuoted(values []string, quote func(string) string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+
+	return strings.Join(quoted, ", ")
 }
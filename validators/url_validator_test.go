@@ -0,0 +1,89 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUrlValidatorHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []UrlValidatorOption
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "allowed host",
+			opts:  []UrlValidatorOption{AllowHosts("myscribae.com")},
+			value: "https://myscribae.com/logo",
+		},
+		{
+			name:  "allowed subdomain",
+			opts:  []UrlValidatorOption{AllowHosts("myscribae.com")},
+			value: "https://cdn.MyScribae.com/logo",
+		},
+		{
+			name:    "host not in allowlist",
+			opts:    []UrlValidatorOption{AllowHosts("myscribae.com")},
+			value:   "https://example.com/logo",
+			wantErr: true,
+		},
+		{
+			name:    "suffix that is not a subdomain",
+			opts:    []UrlValidatorOption{AllowHosts("myscribae.com")},
+			value:   "https://notmyscribae.com/logo",
+			wantErr: true,
+		},
+		{
+			name:    "denied host",
+			opts:    []UrlValidatorOption{DenyHosts(LocalUrlHosts...)},
+			value:   "https://localhost:8080/logo",
+			wantErr: true,
+		},
+		{
+			name:    "denied ipv6 host",
+			opts:    []UrlValidatorOption{DenyHosts(LocalUrlHosts...)},
+			value:   "https://[::1]/logo",
+			wantErr: true,
+		},
+		{
+			name:    "denied subdomain",
+			opts:    []UrlValidatorOption{DenyHosts("example.com")},
+			value:   "https://www.example.com/logo",
+			wantErr: true,
+		},
+		{
+			name:  "host not in denylist",
+			opts:  []UrlValidatorOption{DenyHosts(LocalUrlHosts...)},
+			value: "https://example.com/logo",
+		},
+		{
+			name:  "no host lists",
+			value: "https://localhost/logo",
+		},
+		{
+			name:    "denylist wins over allowlist",
+			opts:    []UrlValidatorOption{AllowHosts("example.com"), DenyHosts("cdn.example.com")},
+			value:   "https://cdn.example.com/logo",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			NewUrlValidator(false, test.opts...).ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("url"),
+				ConfigValue: types.StringValue(test.value),
+			}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Errorf("got error %t, want %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}